@@ -0,0 +1,13 @@
+package bricklinkstore
+
+import "github.com/andrewarchi/brick-apis/money"
+
+// Amount is a price with the 4 decimal places that BrickLink uses. It is
+// encoded in JSON as a string, such as "1.2300", and sums exactly. Use
+// Float64 for a float.
+type Amount = money.Decimal
+
+// ParseAmount parses a price such as "1.2300".
+func ParseAmount(s string) (Amount, error) {
+	return money.ParseDecimal(s)
+}
@@ -0,0 +1,29 @@
+package bricklinkstore
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmountJSON(t *testing.T) {
+	in := `{"currency_code":"USD","subtotal":"0.3000","grand_total":"0.3000","etc1":"0.0000","etc2":"0.0000","insurance":"0.0000","shipping":"0.0000","credit":"0.0000","coupon":"0.0000","salesTax":"0.0000","vat_rate":"0.0000","vat_amount":"0.0000"}`
+	var cost Cost
+	if err := json.Unmarshal([]byte(in), &cost); err != nil {
+		t.Fatal(err)
+	}
+	// Summing as floats gives 0.30000000000000004.
+	tenth, _ := ParseAmount("0.1")
+	if sum := tenth + tenth + tenth; sum != cost.Subtotal {
+		t.Errorf("got %s, want %s", sum, cost.Subtotal)
+	}
+	if f := cost.GrandTotal.Float64(); f != 0.3 {
+		t.Errorf("Float64 = %v, want 0.3", f)
+	}
+	out, err := json.Marshal(cost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != in {
+		t.Errorf("round trip mismatch:\ngot  %s\nwant %s", out, in)
+	}
+}
@@ -0,0 +1,276 @@
+package bricklinkstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint is a group of API endpoints that share a cache TTL.
+type Endpoint string
+
+// Cacheable endpoints. Orders are never cached.
+const (
+	EndpointItem        Endpoint = "item"         // GetItem and GetItemImage
+	EndpointSupersets   Endpoint = "supersets"    // GetSupersets and GetSupersetsByColor
+	EndpointSubsets     Endpoint = "subsets"      // GetSubsets and GetSubsetsByColor
+	EndpointKnownColors Endpoint = "known_colors" // GetKnownColors
+	EndpointPriceGuide  Endpoint = "price_guide"  // GetPriceGuide
+	EndpointColors      Endpoint = "colors"       // GetColors and GetColor
+)
+
+// DefaultCacheTTLs are the TTLs used when SetCache is given none. Price
+// guides are not cached by default, since they change daily.
+var DefaultCacheTTLs = map[Endpoint]time.Duration{
+	EndpointItem:        30 * 24 * time.Hour,
+	EndpointSupersets:   7 * 24 * time.Hour,
+	EndpointSubsets:     30 * 24 * time.Hour,
+	EndpointKnownColors: 7 * 24 * time.Hour,
+	EndpointColors:      90 * 24 * time.Hour,
+}
+
+// endpointOf classifies a request URL such as "/items/PART/3001/subsets".
+func endpointOf(url string) (Endpoint, bool) {
+	if i := strings.IndexByte(url, '?'); i != -1 {
+		url = url[:i]
+	}
+	parts := strings.Split(strings.Trim(url, "/"), "/")
+	switch {
+	case parts[0] == "colors" && len(parts) <= 2:
+		return EndpointColors, true
+	case parts[0] != "items" || len(parts) < 3:
+		return "", false
+	case len(parts) == 3 || (len(parts) == 5 && parts[3] == "images"):
+		return EndpointItem, true
+	case len(parts) == 4:
+		switch parts[3] {
+		case "supersets":
+			return EndpointSupersets, true
+		case "subsets":
+			return EndpointSubsets, true
+		case "colors":
+			return EndpointKnownColors, true
+		case "price":
+			return EndpointPriceGuide, true
+		}
+	}
+	return "", false
+}
+
+// CacheEntry is a cached response body.
+type CacheEntry struct {
+	Key          string    `json:"key"`
+	Data         []byte    `json:"data"`
+	Stored       time.Time `json:"stored"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+}
+
+// Cache stores response bodies by request URL.
+type Cache interface {
+	// Get returns the entry for a key, or nil when there is none.
+	Get(key string) (*CacheEntry, error)
+	Put(entry *CacheEntry) error
+}
+
+// CacheMissError is returned in offline mode for a request that is not
+// in the cache.
+type CacheMissError struct {
+	URL string
+}
+
+func (err *CacheMissError) Error() string {
+	return fmt.Sprintf("bricklinkstore: offline and %s is not cached", err.URL)
+}
+
+// IsCacheMiss reports whether err is a *CacheMissError.
+func IsCacheMiss(err error) bool {
+	var miss *CacheMissError
+	return errors.As(err, &miss)
+}
+
+// NotCacheableError is returned in offline mode for a request to an
+// endpoint that is never cached, such as orders, or that is not cached
+// with the client's TTLs.
+type NotCacheableError struct {
+	URL string
+}
+
+func (err *NotCacheableError) Error() string {
+	return fmt.Sprintf("bricklinkstore: offline and %s is not cacheable", err.URL)
+}
+
+// IsNotCacheable reports whether err is a *NotCacheableError.
+func IsNotCacheable(err error) bool {
+	var nc *NotCacheableError
+	return errors.As(err, &nc)
+}
+
+var errNoCache = errors.New("bricklinkstore: no cache set")
+
+// SetCache caches responses of the cacheable endpoints. Responses are
+// refreshed once older than the TTL of their endpoint; endpoints missing
+// from ttls are not cached. When ttls is nil, DefaultCacheTTLs is used.
+func (c *Client) SetCache(cache Cache, ttls map[Endpoint]time.Duration) {
+	if ttls == nil {
+		ttls = DefaultCacheTTLs
+	}
+	c.cache = cache
+	c.cacheTTLs = ttls
+}
+
+// SetOffline sets whether requests are served only from the cache,
+// regardless of age. In offline mode, requests that miss the cache fail
+// with a *CacheMissError instead of using the network, and requests that
+// cannot be cached fail with a *NotCacheableError.
+func (c *Client) SetOffline(offline bool) {
+	c.offline = offline
+}
+
+// cached returns the cached response for url, if fresh, and the entry to
+// refresh otherwise. Errors reading the cache are only returned offline.
+func (c *Client) cached(url string) (data []byte, stale *CacheEntry, cacheable bool, err error) {
+	if c.cache == nil {
+		return nil, nil, false, nil
+	}
+	endpoint, ok := endpointOf(url)
+	ttl := c.cacheTTLs[endpoint]
+	if !ok || ttl <= 0 {
+		return nil, nil, false, nil
+	}
+	entry, err := c.cache.Get(url)
+	if err != nil && c.offline {
+		return nil, nil, true, err
+	}
+	if err != nil || entry == nil {
+		return nil, nil, true, nil // Fetch unreadable entries again when online
+	}
+	if c.offline || time.Since(entry.Stored) < ttl {
+		return entry.Data, nil, true, nil
+	}
+	return nil, entry, true, nil
+}
+
+// WarmItems caches the catalog entries of items. It returns the errors of
+// items that failed by item number.
+func (c *Client) WarmItems(itemType ItemType, itemNos []string) (map[string]error, error) {
+	return c.warm(itemNos, func(itemNo string) error {
+		_, err := c.GetItem(itemType, itemNo)
+		return err
+	})
+}
+
+// WarmSubsets caches the subsets of items, such as the inventories of sets,
+// without boxes or instructions.
+func (c *Client) WarmSubsets(itemType ItemType, itemNos []string, breakMinifigs, breakSubsets bool) (map[string]error, error) {
+	return c.warm(itemNos, func(itemNo string) error {
+		_, err := c.GetSubsets(itemType, itemNo, false, false, breakMinifigs, breakSubsets)
+		return err
+	})
+}
+
+// WarmSupersets caches the supersets of items.
+func (c *Client) WarmSupersets(itemType ItemType, itemNos []string) (map[string]error, error) {
+	return c.warm(itemNos, func(itemNo string) error {
+		_, err := c.GetSupersets(itemType, itemNo)
+		return err
+	})
+}
+
+// WarmKnownColors caches the known colors of items.
+func (c *Client) WarmKnownColors(itemType ItemType, itemNos []string) (map[string]error, error) {
+	return c.warm(itemNos, func(itemNo string) error {
+		_, err := c.GetKnownColors(itemType, itemNo)
+		return err
+	})
+}
+
+// warm calls get for each unique item number with a bounded number of
+// parallel, throttled requests. Fresh entries are left as they are.
+func (c *Client) warm(itemNos []string, get func(itemNo string) error) (map[string]error, error) {
+	if c.cache == nil {
+		return nil, errNoCache
+	}
+	var unique []string
+	seen := make(map[string]bool)
+	for _, no := range itemNos {
+		if !seen[no] {
+			seen[no] = true
+			unique = append(unique, no)
+		}
+	}
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	c.run(len(unique), func(i int) {
+		if err := get(unique[i]); err != nil {
+			mu.Lock()
+			errs[unique[i]] = err
+			mu.Unlock()
+		}
+	})
+	return errs, nil
+}
+
+// DiskCache stores entries as files in a directory.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a cache in a directory, creating it if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir}, nil
+}
+
+func (dc *DiskCache) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the entry for a key, or nil when there is none.
+func (dc *DiskCache) Get(key string) (*CacheEntry, error) {
+	data, err := ioutil.ReadFile(dc.filename(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, nil // Treat corrupt entries as missing
+	}
+	return &entry, nil
+}
+
+// Put stores an entry, replacing any with the same key.
+func (dc *DiskCache) Put(entry *CacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(dc.dir, ".entry")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), dc.filename(entry.Key))
+}
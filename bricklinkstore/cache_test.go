@@ -0,0 +1,181 @@
+package bricklinkstore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEndpointOf(t *testing.T) {
+	tests := []struct {
+		url      string
+		endpoint Endpoint
+		ok       bool
+	}{
+		{"/items/PART/3001", EndpointItem, true},
+		{"/items/PART/3001/images/5", EndpointItem, true},
+		{"/items/SET/75192-1/subsets?box=false", EndpointSubsets, true},
+		{"/items/PART/3001/supersets?color_id=5", EndpointSupersets, true},
+		{"/items/PART/3001/colors", EndpointKnownColors, true},
+		{"/items/PART/3001/price?guide_type=sold", EndpointPriceGuide, true},
+		{"/colors", EndpointColors, true},
+		{"/colors/5", EndpointColors, true},
+		{"/orders?direction=in", "", false},
+		{"/orders/1234/items", "", false},
+	}
+	for _, tt := range tests {
+		endpoint, ok := endpointOf(tt.url)
+		if endpoint != tt.endpoint || ok != tt.ok {
+			t.Errorf("endpointOf(%q) = %q, %t, want %q, %t", tt.url, endpoint, ok, tt.endpoint, tt.ok)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bricklinkstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cache, err := NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	var notModified int
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		switch r.URL.Path {
+		case "/colors":
+			fmt.Fprint(w, `{"meta":{"description":"OK","message":"OK","code":200},"data":[{"color_id":1,"color_name":"White","color_code":"FFFFFF","color_type":"Solid"}]}`)
+		case "/orders":
+			fmt.Fprint(w, `{"meta":{"description":"OK","message":"OK","code":200},"data":[]}`)
+		case "/items/PART/missing":
+			fmt.Fprint(w, `{"meta":{"description":"not found","message":"RESOURCE_NOT_FOUND","code":404},"data":{}}`)
+		default:
+			fmt.Fprintf(w, `{"meta":{"description":"OK","message":"OK","code":200},"data":[{"match_no":0,"entries":[{"item":{"no":"3001","name":"Brick 2 x 4","type":"PART","category_id":5},"color_id":5,"quantity":1,"extra_quantity":0,"is_alternate":false,"is_counterpart":false}]}]}`)
+		}
+	})
+	defer done()
+	ttls := map[Endpoint]time.Duration{EndpointColors: time.Hour, EndpointSubsets: time.Hour, EndpointItem: time.Hour}
+	c.SetCache(cache, ttls)
+
+	for i := 0; i < 2; i++ {
+		colors, err := c.GetColors()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(colors) != 1 || colors[0].ColorName != "White" {
+			t.Errorf("unexpected colors %v", colors)
+		}
+	}
+	if requests["/colors"] != 1 {
+		t.Errorf("colors requested %d times, want 1", requests["/colors"])
+	}
+
+	// Expired entries are refreshed conditionally.
+	ttls[EndpointColors] = time.Nanosecond
+	if _, err := c.GetColors(); err != nil {
+		t.Fatal(err)
+	}
+	if requests["/colors"] != 2 || notModified != 1 {
+		t.Errorf("expected a conditional refresh, got %d requests and %d not modified", requests["/colors"], notModified)
+	}
+
+	// Errors are not cached.
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetItem(ItemTypePart, "missing"); err == nil {
+			t.Error("expected error for missing item")
+		}
+	}
+	if requests["/items/PART/missing"] != 2 {
+		t.Errorf("missing item requested %d times, want 2", requests["/items/PART/missing"])
+	}
+
+	errs, err := c.WarmSubsets(ItemTypeSet, []string{"1-1", "2-1", "1-1"}, false, false)
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("warming failed: %v %v", errs, err)
+	}
+
+	c.SetOffline(true)
+	before := len(requests)
+	if _, err := c.GetSubsets(ItemTypeSet, "2-1", false, false, false, false); err != nil {
+		t.Errorf("expected warmed subsets offline, got %v", err)
+	}
+	if _, err := c.GetColors(); err != nil {
+		t.Errorf("expected stale colors offline, got %v", err)
+	}
+	if _, err := c.GetSubsets(ItemTypeSet, "3-1", false, false, false, false); !IsCacheMiss(err) {
+		t.Errorf("expected cache miss, got %v", err)
+	}
+	if _, err := c.GetOrders("in"); !IsNotCacheable(err) || IsCacheMiss(err) {
+		t.Errorf("expected orders to be not cacheable, got %v", err)
+	}
+	if len(requests) != before || requests["/items/SET/1-1/subsets"] != 1 {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
+
+// brokenCache fails to read entries and returns corrupt data for others.
+type brokenCache struct {
+	corrupt map[string]bool
+}
+
+func (bc *brokenCache) Get(key string) (*CacheEntry, error) {
+	if bc.corrupt[key] {
+		return &CacheEntry{Key: key, Data: []byte("{"), Stored: time.Now()}, nil
+	}
+	return nil, errors.New("unreadable entry")
+}
+
+func (bc *brokenCache) Put(entry *CacheEntry) error { return nil }
+
+func TestCacheReadErrors(t *testing.T) {
+	var requests int
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		color := `{"color_id":1,"color_name":"White","color_code":"FFFFFF","color_type":"Solid"}`
+		if r.URL.Path == "/colors" {
+			color = "[" + color + "]"
+		}
+		fmt.Fprintf(w, `{"meta":{"description":"OK","message":"OK","code":200},"data":%s}`, color)
+	})
+	defer done()
+	c.SetCache(&brokenCache{corrupt: map[string]bool{"/colors/1": true}}, nil)
+
+	if _, err := c.GetColors(); err != nil {
+		t.Errorf("expected unreadable entry to be fetched online, got %v", err)
+	}
+	if color, err := c.GetColor(1); err != nil || color.ColorName != "White" {
+		t.Errorf("expected corrupt entry to be fetched online, got %v %v", color, err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+
+	c.SetOffline(true)
+	if _, err := c.GetColors(); err == nil || IsCacheMiss(err) {
+		t.Errorf("expected read error offline, got %v", err)
+	}
+	if _, err := c.GetColor(1); err == nil {
+		t.Error("expected corrupt entry to fail offline")
+	}
+	if requests != 2 {
+		t.Errorf("expected no requests offline, got %d", requests-2)
+	}
+}
@@ -116,7 +116,7 @@ func toParams(o *PriceGuideOptions) string {
 	if o == nil {
 		return ""
 	}
-	var params url.Values
+	params := url.Values{}
 	if o.ColorID != 0 {
 		params.Set("color_id", strconv.Itoa(o.ColorID))
 	}
@@ -203,22 +203,22 @@ type SubsetEntry struct {
 }
 
 type PriceGuide struct {
-	Item          CatalogItem   `json:"item"`                 // An object representation of the item
-	NewOrUsed     string        `json:"new_or_used"`          // Indicates whether the price guide is for new or used (N: New, U: Used)
-	CurrencyCode  string        `json:"currency_code"`        // The currency code of the price
-	MinPrice      float64       `json:"min_price,string"`     // The lowest price of the item (in stock / that was sold for last 6 months)
-	MaxPrice      float64       `json:"max_price,string"`     // The highest price of the item (in stock / that was sold for last 6 months)
-	AvgPrice      float64       `json:"avg_price,string"`     // The average price of the item (in stock / that was sold for last 6 months)
-	QtyAvgPrice   float64       `json:"qty_avg_price,string"` // The average price of the item (in stock / that was sold for last 6 months) by quantity
-	UnitQuantity  int           `json:"unit_quantity"`        // The number of inventories that include the item / The number of times the item has been sold for last 6 months
-	TotalQuantity int           `json:"total_quantity"`       // The total number of the items in stock / The number of items has been sold for last 6 months
-	PriceDetail   []PriceDetail `json:"price_detail"`         // A list of objects that represent the detailed information of the price
+	Item          CatalogItem   `json:"item"`           // An object representation of the item
+	NewOrUsed     string        `json:"new_or_used"`    // Indicates whether the price guide is for new or used (N: New, U: Used)
+	CurrencyCode  string        `json:"currency_code"`  // The currency code of the price
+	MinPrice      Amount        `json:"min_price"`      // The lowest price of the item (in stock / that was sold for last 6 months)
+	MaxPrice      Amount        `json:"max_price"`      // The highest price of the item (in stock / that was sold for last 6 months)
+	AvgPrice      Amount        `json:"avg_price"`      // The average price of the item (in stock / that was sold for last 6 months)
+	QtyAvgPrice   Amount        `json:"qty_avg_price"`  // The average price of the item (in stock / that was sold for last 6 months) by quantity
+	UnitQuantity  int           `json:"unit_quantity"`  // The number of inventories that include the item / The number of times the item has been sold for last 6 months
+	TotalQuantity int           `json:"total_quantity"` // The total number of the items in stock / The number of items has been sold for last 6 months
+	PriceDetail   []PriceDetail `json:"price_detail"`   // A list of objects that represent the detailed information of the price
 }
 
 type PriceDetail struct {
 	Quantity           int         `json:"quantity"`            // The number of the items in the inventory
 	QuantityDeprecated int         `json:"qunatity"`            // Deprecated, typo
-	UnitPrice          float64     `json:"unit_price,string"`   // The original price of this item per sale unit
+	UnitPrice          Amount      `json:"unit_price"`          // The original price of this item per sale unit
 	ShippingAvailable  bool        `json:"shipping_available"`  // Indicates whether or not the seller ships to your country (based on the user profile). Only included for in stock
 	SellerCountryCode  CountryCode `json:"seller_country_code"` // The country code of the seller's location. Only included for last 6 months.
 	BuyerCountryCode   CountryCode `json:"buyer_country_code"`  // The country code of the buyer's location. Only included for last 6 months.
@@ -1,12 +1,16 @@
 package bricklinkstore
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/andrewarchi/brick-apis/internal/pool"
 	"github.com/mrjones/oauth"
 )
 
@@ -14,7 +18,14 @@ const base = "https://api.bricklink.com/api/store/v1"
 
 // Client connects to the BrickLink store API.
 type Client struct {
-	client *http.Client
+	client      *http.Client
+	base        string
+	workers     int
+	throttle    *pool.Throttle
+	priceGuides *priceGuideCache
+	cache       Cache
+	cacheTTLs   map[Endpoint]time.Duration
+	offline     bool
 }
 
 // NewClient constructs a client for the BrickLink store API.
@@ -22,19 +33,112 @@ func NewClient(consumerKey, consumerSecret, token, tokenSecret string) (*Client,
 	consumer := oauth.NewConsumer(consumerKey, consumerSecret, oauth.ServiceProvider{})
 	accessToken := &oauth.AccessToken{Token: token, Secret: tokenSecret}
 	client, err := consumer.MakeHttpClient(accessToken)
-	return &Client{client}, err
+	return &Client{
+		client:      client,
+		base:        base,
+		workers:     defaultWorkers,
+		throttle:    pool.NewThrottle(defaultDelay),
+		priceGuides: newPriceGuideCache(DefaultPriceGuideTTL),
+	}, err
 }
 
 func (c *Client) doGet(url string, v interface{}) error {
-	resp, err := c.client.Get(base + url)
+	data, stale, cacheable, err := c.cached(url)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode/100 != 2 {
-		return fmt.Errorf("status %s", resp.Status)
+	if data != nil {
+		err := decode(data, v)
+		if err == nil || c.offline {
+			return err
+		}
+		// Fetch corrupt entries again when online.
+	}
+	if c.offline {
+		if !cacheable {
+			return &NotCacheableError{url}
+		}
+		return &CacheMissError{url}
+	}
+	if !cacheable {
+		resp, err := c.get(url, nil)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		decoder := json.NewDecoder(resp.Body)
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(v)
+	}
+
+	// Refresh conditionally when the cached response has validators.
+	header := make(http.Header)
+	if stale != nil {
+		if stale.ETag != "" {
+			header.Set("If-None-Match", stale.ETag)
+		}
+		if stale.LastModified != "" {
+			header.Set("If-Modified-Since", stale.LastModified)
+		}
+	}
+	resp, err := c.get(url, header)
+	if err != nil {
+		return err
 	}
 	defer resp.Body.Close()
-	decoder := json.NewDecoder(resp.Body)
+	if resp.StatusCode == http.StatusNotModified {
+		stale.Stored = time.Now()
+		if err := c.cache.Put(stale); err != nil {
+			return err
+		}
+		return decode(stale.Data, v)
+	}
+	data, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := decode(data, v); err != nil {
+		return err
+	}
+	// Only cache successful responses.
+	var r struct {
+		Meta meta `json:"meta"`
+	}
+	if json.Unmarshal(data, &r) == nil && checkMeta(r.Meta) == nil {
+		return c.cache.Put(&CacheEntry{
+			Key:          url,
+			Data:         data,
+			Stored:       time.Now(),
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		})
+	}
+	return nil
+}
+
+// get requests a URL. A 304 Not Modified
+// response is only accepted when header makes the request conditional.
+func (c *Client) get(url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest("GET", c.base+url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 && !(resp.StatusCode == http.StatusNotModified && len(header) != 0) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
 	decoder.DisallowUnknownFields()
 	return decoder.Decode(v)
 }
@@ -46,7 +150,7 @@ func (c *Client) doGetAndSave(url string, v interface{}, filename string) error
 	}
 	defer file.Close()
 
-	resp, err := c.client.Get(base + url)
+	resp, err := c.client.Get(c.base + url)
 	if err != nil {
 		return err
 	}
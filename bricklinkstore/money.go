@@ -0,0 +1,34 @@
+package bricklinkstore
+
+import "github.com/andrewarchi/brick-apis/money"
+
+func toMoney(amount Amount, currency CurrencyCode) money.Money {
+	return money.New(amount, money.Currency(currency))
+}
+
+// Total returns the grand total in the currency of the cost.
+func (c *Cost) Total() money.Money {
+	return toMoney(c.GrandTotal, c.CurrencyCode)
+}
+
+// ObserveRates records the exchange rate implied by the grand totals of the
+// order in the store currency and in the display currency of the user.
+func (o *Order) ObserveRates(rates *money.ImpliedRates) {
+	rates.Observe(o.Cost.Total(), o.DisplayCost.Total())
+}
+
+// UnitPriceFinalMoney returns the final unit price in the store currency.
+func (i *OrderItem) UnitPriceFinalMoney() money.Money {
+	return toMoney(i.UnitPriceFinal, i.CurrencyCode)
+}
+
+// ObserveRates records the exchange rate implied by the final unit price of
+// the item in the store currency and in the display currency of the user.
+func (i *OrderItem) ObserveRates(rates *money.ImpliedRates) {
+	rates.Observe(i.UnitPriceFinalMoney(), toMoney(i.UnitPriceFinalDisplay, i.CurrencyCodeDisplay))
+}
+
+// Money returns the average price of the price guide.
+func (pg *PriceGuide) Money() money.Money {
+	return toMoney(pg.AvgPrice, CurrencyCode(pg.CurrencyCode))
+}
@@ -133,39 +133,39 @@ type PersonName struct {
 
 // Cost contains cost information for an order
 type Cost struct {
-	CurrencyCode CurrencyCode `json:"currency_code"`      // The currency code
-	Subtotal     float64      `json:"subtotal,string"`    // The total price for the order exclusive of shipping and other costs. This must equal the sum of all the items
-	GrandTotal   float64      `json:"grand_total,string"` // The total price for the order inclusive of tax, shipping and other costs
-	Etc1         float64      `json:"etc1,string"`        // Extra charge for this order (tax, packing, etc.)
-	Etc2         float64      `json:"etc2,string"`        // Extra charge for this order (tax, packing, etc.)
-	Insurance    float64      `json:"insurance,string"`   // Insurance cost
-	Shipping     float64      `json:"shipping,string"`    // Shipping cost
-	Credit       float64      `json:"credit,string"`      // Credit applied to this order
-	Coupon       float64      `json:"coupon,string"`      // Amount of coupon discount
-	SalesTax     float64      `json:"salesTax,string"`
-	VATRate      float64      `json:"vat_rate,string"`   // VAT percentage applied to this order
-	VATAmount    float64      `json:"vat_amount,string"` // Total amount of VAT included in the grand_total price
+	CurrencyCode CurrencyCode `json:"currency_code"` // The currency code
+	Subtotal     Amount       `json:"subtotal"`      // The total price for the order exclusive of shipping and other costs. This must equal the sum of all the items
+	GrandTotal   Amount       `json:"grand_total"`   // The total price for the order inclusive of tax, shipping and other costs
+	Etc1         Amount       `json:"etc1"`          // Extra charge for this order (tax, packing, etc.)
+	Etc2         Amount       `json:"etc2"`          // Extra charge for this order (tax, packing, etc.)
+	Insurance    Amount       `json:"insurance"`     // Insurance cost
+	Shipping     Amount       `json:"shipping"`      // Shipping cost
+	Credit       Amount       `json:"credit"`        // Credit applied to this order
+	Coupon       Amount       `json:"coupon"`        // Amount of coupon discount
+	SalesTax     Amount       `json:"salesTax"`
+	VATRate      Amount       `json:"vat_rate"`   // VAT percentage applied to this order
+	VATAmount    Amount       `json:"vat_amount"` // Total amount of VAT included in the grand_total price
 }
 
 // OrderItem is an item contained in an order
 type OrderItem struct {
-	InventoryID           int          `json:"inventory_id"`                 // The ID of the inventory that includes the item
-	Item                  CatalogItem  `json:"item"`                         // An object representation of the item
-	ColorID               int          `json:"color_id"`                     // The ID of the color of the item
-	ColorName             string       `json:"color_name"`                   // Color name of the item
-	Quantity              int          `json:"quantity"`                     // The number of items purchased in this order
-	NewOrUsed             NewOrUsed    `json:"new_or_used"`                  // Indicates whether the item is new or used (N: New, U: Used)
-	Completeness          Completeness `json:"completeness,omitempty"`       // Indicates whether the set is complete or incomplete. This value is valid only for SET type. (C: Complete, B: Incomplete, S: Sealed)
-	UnitPrice             float64      `json:"unit_price,string"`            // The original price of this item per sale unit
-	UnitPriceFinal        float64      `json:"unit_price_final,string"`      // The unit price of this item after applying tiered pricing policy
-	UnitPriceDisplay      float64      `json:"disp_unit_price,string"`       // The original price of this item per sale unit in display currency of the user
-	UnitPriceFinalDisplay float64      `json:"disp_unit_price_final,string"` // The unit price of this item after applying tiered pricing policy in display currency of the user
-	CurrencyCode          CurrencyCode `json:"currency_code"`                // The currency code of the price
-	CurrencyCodeDisplay   CurrencyCode `json:"disp_currency_code"`           // The display currency code of the user
-	Description           string       `json:"description"`                  // User remarks of the order item
-	Remarks               string       `json:"remarks"`                      // User description of the order item
-	Weight                float64      `json:"weight,string"`                // The weight of the item that overrides the catalog weight
-	OrderCost             float64      `json:"order_cost,string"`
+	InventoryID           int          `json:"inventory_id"`           // The ID of the inventory that includes the item
+	Item                  CatalogItem  `json:"item"`                   // An object representation of the item
+	ColorID               int          `json:"color_id"`               // The ID of the color of the item
+	ColorName             string       `json:"color_name"`             // Color name of the item
+	Quantity              int          `json:"quantity"`               // The number of items purchased in this order
+	NewOrUsed             NewOrUsed    `json:"new_or_used"`            // Indicates whether the item is new or used (N: New, U: Used)
+	Completeness          Completeness `json:"completeness,omitempty"` // Indicates whether the set is complete or incomplete. This value is valid only for SET type. (C: Complete, B: Incomplete, S: Sealed)
+	UnitPrice             Amount       `json:"unit_price"`             // The original price of this item per sale unit
+	UnitPriceFinal        Amount       `json:"unit_price_final"`       // The unit price of this item after applying tiered pricing policy
+	UnitPriceDisplay      Amount       `json:"disp_unit_price"`        // The original price of this item per sale unit in display currency of the user
+	UnitPriceFinalDisplay Amount       `json:"disp_unit_price_final"`  // The unit price of this item after applying tiered pricing policy in display currency of the user
+	CurrencyCode          CurrencyCode `json:"currency_code"`          // The currency code of the price
+	CurrencyCodeDisplay   CurrencyCode `json:"disp_currency_code"`     // The display currency code of the user
+	Description           string       `json:"description"`            // User remarks of the order item
+	Remarks               string       `json:"remarks"`                // User description of the order item
+	Weight                float64      `json:"weight,string"`          // The weight of the item that overrides the catalog weight
+	OrderCost             Amount       `json:"order_cost"`
 }
 
 type OrderStatus string
@@ -0,0 +1,179 @@
+package bricklinkstore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PartOutOptions contains the parameters of PartOutValue.
+type PartOutOptions struct {
+	GuideType     GuideType
+	NewOrUsed     NewOrUsed
+	CountryCode   CountryCode
+	Region        Region
+	CurrencyCode  CurrencyCode
+	VAT           IncludeVAT
+	BreakMinifigs bool // Value minifigs by their parts
+	BreakSubsets  bool // Value sub-sets by their parts
+}
+
+func (o *PartOutOptions) priceGuideOptions(colorID int) *PriceGuideOptions {
+	return &PriceGuideOptions{
+		ColorID:      colorID,
+		GuideType:    o.GuideType,
+		NewOrUsed:    o.NewOrUsed,
+		CountryCode:  o.CountryCode,
+		Region:       o.Region,
+		CurrencyCode: o.CurrencyCode,
+		VAT:          o.VAT,
+	}
+}
+
+// PartOutEntry is the value of an item in the inventory of a set.
+type PartOutEntry struct {
+	Item          CatalogItem
+	ColorID       int
+	Quantity      int
+	ExtraQuantity int
+	MatchNo       int
+	IsAlternate   bool
+	IsCounterpart bool
+	UnitPrice     Amount // Average price from the price guide
+	PriceGuide    *PriceGuide
+}
+
+// Value returns the value of the regular quantity.
+func (e *PartOutEntry) Value() Amount {
+	return e.UnitPrice.MulInt(e.Quantity)
+}
+
+// ExtraValue returns the value of the extra quantity.
+func (e *PartOutEntry) ExtraValue() Amount {
+	return e.UnitPrice.MulInt(e.ExtraQuantity)
+}
+
+// PartOut is the expected value of selling a set as parts.
+type PartOut struct {
+	SetNo             string
+	CurrencyCode      string
+	Entries           []PartOutEntry
+	PartsValue        Amount // Regular items other than minifigs
+	MinifigsValue     Amount // Whole minifigs, unless broken up
+	ExtrasValue       Amount // Extra quantities of regular items
+	AlternatesValue   Amount // Alternate items, not included in Total
+	CounterpartsValue Amount // Counterpart items, not included in Total
+	SetPriceGuide     *PriceGuide
+}
+
+// Total returns the value of the regular items and minifigs.
+func (p *PartOut) Total() Amount {
+	return p.PartsValue + p.MinifigsValue
+}
+
+// TotalWithExtras returns the total including extra quantities.
+func (p *PartOut) TotalWithExtras() Amount {
+	return p.Total() + p.ExtrasValue
+}
+
+// SetPrice returns the average price of the set itself.
+func (p *PartOut) SetPrice() Amount {
+	if p.SetPriceGuide == nil {
+		return 0
+	}
+	return p.SetPriceGuide.AvgPrice
+}
+
+// Ratio returns the part-out value relative to the price of the set, or 0
+// when the set has no price.
+func (p *PartOut) Ratio() float64 {
+	if setPrice := p.SetPrice(); setPrice != 0 {
+		return p.Total().Float64() / setPrice.Float64()
+	}
+	return 0
+}
+
+// PartOutValue calculates the value of selling a set as parts from the
+// price guide of each item in its inventory and compares it with the price
+// guide of the set. The set number must include its variant, as in
+// "75192-1".
+func (c *Client) PartOutValue(setNo string, options *PartOutOptions) (*PartOut, error) {
+	if options == nil {
+		options = &PartOutOptions{}
+	}
+	if i := strings.LastIndexByte(setNo, '-'); i <= 0 || !isVariant(setNo[i+1:]) {
+		return nil, fmt.Errorf("set number %q has no variant, such as %q", setNo, strings.TrimRight(setNo, "-")+"-1")
+	}
+	subsets, err := c.GetSubsets(ItemTypeSet, setNo, false, false, options.BreakMinifigs, options.BreakSubsets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch the price guides of the set and each unique item in bulk.
+	requests := []PriceGuideRequest{{ItemTypeSet, setNo, *options.priceGuideOptions(0)}}
+	type guideKey struct {
+		itemType ItemType
+		no       string
+		colorID  int
+	}
+	indexes := make(map[guideKey]int)
+	for _, subset := range subsets {
+		for _, entry := range subset.Entries {
+			key := guideKey{entry.Item.Type, entry.Item.No, entry.ColorID}
+			if _, ok := indexes[key]; !ok {
+				indexes[key] = len(requests)
+				requests = append(requests, PriceGuideRequest{entry.Item.Type, entry.Item.No, *options.priceGuideOptions(entry.ColorID)})
+			}
+		}
+	}
+	results := c.GetPriceGuides(requests)
+	if err := results[0].Err; err != nil {
+		return nil, fmt.Errorf("price guide for set %s: %v", setNo, err)
+	}
+	for _, r := range results[1:] {
+		if r.Err != nil {
+			return nil, fmt.Errorf("price guide for %s %s in color %d: %v", r.Request.ItemType, r.Request.ItemNo, r.Request.Options.ColorID, r.Err)
+		}
+	}
+
+	p := &PartOut{SetNo: setNo, SetPriceGuide: results[0].PriceGuide}
+	for _, subset := range subsets {
+		for _, entry := range subset.Entries {
+			guide := results[indexes[guideKey{entry.Item.Type, entry.Item.No, entry.ColorID}]].PriceGuide
+			e := PartOutEntry{
+				Item:          entry.Item,
+				ColorID:       entry.ColorID,
+				Quantity:      entry.Quantity,
+				ExtraQuantity: entry.ExtraQuantity,
+				MatchNo:       subset.MatchNo,
+				IsAlternate:   entry.IsAlternate,
+				IsCounterpart: entry.IsCounterpart,
+				UnitPrice:     guide.AvgPrice,
+				PriceGuide:    guide,
+			}
+			if p.CurrencyCode == "" {
+				p.CurrencyCode = guide.CurrencyCode
+			}
+			switch {
+			case e.IsAlternate:
+				p.AlternatesValue += e.Value()
+			case e.IsCounterpart:
+				p.CounterpartsValue += e.Value()
+			case e.Item.Type == ItemTypeMinifig:
+				p.MinifigsValue += e.Value()
+				p.ExtrasValue += e.ExtraValue()
+			default:
+				p.PartsValue += e.Value()
+				p.ExtrasValue += e.ExtraValue()
+			}
+			p.Entries = append(p.Entries, e)
+		}
+	}
+	return p, nil
+}
+
+// isVariant reports whether s is a set variant, a positive number.
+func isVariant(s string) bool {
+	v, err := strconv.Atoi(s)
+	return err == nil && v > 0
+}
@@ -0,0 +1,112 @@
+package bricklinkstore
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newTestClient(handler http.HandlerFunc) (*Client, func()) {
+	server := httptest.NewServer(handler)
+	return &Client{client: server.Client(), base: server.URL}, server.Close
+}
+
+func TestPartOutValue(t *testing.T) {
+	prices := map[string]string{
+		"/items/PART/3001/price":      "0.50",
+		"/items/PART/3002/price":      "0.30",
+		"/items/PART/3003/price":      "0.20",
+		"/items/PART/3004/price":      "0.10",
+		"/items/MINIFIG/sw0001/price": "12.00",
+		"/items/SET/1234-1/price":     "20.00",
+	}
+	var mu sync.Mutex
+	var guideRequests int
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		meta := `{"description":"OK","message":"OK","code":200}`
+		if r.URL.Path == "/items/SET/1234-1/subsets" {
+			if got := r.URL.Query().Get("break_minifigs"); got != "false" {
+				t.Errorf("break_minifigs = %q", got)
+			}
+			fmt.Fprintf(w, `{"meta":%s,"data":[
+				{"match_no":0,"entries":[{"item":{"no":"3001","name":"Brick 2 x 4","type":"PART","category_id":5},"color_id":5,"quantity":4,"extra_quantity":1,"is_alternate":false,"is_counterpart":false}]},
+				{"match_no":0,"entries":[{"item":{"no":"sw0001","name":"Luke","type":"MINIFIG","category_id":65},"color_id":0,"quantity":1,"extra_quantity":0,"is_alternate":false,"is_counterpart":false}]},
+				{"match_no":1,"entries":[
+					{"item":{"no":"3002","name":"Brick 2 x 3","type":"PART","category_id":5},"color_id":5,"quantity":2,"extra_quantity":0,"is_alternate":false,"is_counterpart":false},
+					{"item":{"no":"3003","name":"Brick 2 x 2","type":"PART","category_id":5},"color_id":5,"quantity":3,"extra_quantity":0,"is_alternate":true,"is_counterpart":false}]},
+				{"match_no":0,"entries":[{"item":{"no":"3004","name":"Brick 1 x 2","type":"PART","category_id":5},"color_id":1,"quantity":5,"extra_quantity":0,"is_alternate":false,"is_counterpart":true}]},
+				{"match_no":0,"entries":[{"item":{"no":"3001","name":"Brick 2 x 4","type":"PART","category_id":5},"color_id":5,"quantity":2,"extra_quantity":0,"is_alternate":false,"is_counterpart":false}]}
+			]}`, meta)
+			return
+		}
+		price, ok := prices[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		guideRequests++
+		mu.Unlock()
+		q := r.URL.Query()
+		if q.Get("new_or_used") != "U" || q.Get("guide_type") != "sold" || q.Get("currency_code") != "EUR" {
+			t.Errorf("unexpected price guide query %s", r.URL.RawQuery)
+		}
+		if strings.HasPrefix(r.URL.Path, "/items/PART/") && q.Get("color_id") == "" {
+			t.Errorf("price guide for %s missing color", r.URL.Path)
+		}
+		fmt.Fprintf(w, `{"meta":%s,"data":{"item":{"no":"x","type":"PART"},"new_or_used":"U","currency_code":"EUR","min_price":"0","max_price":"0","avg_price":%q,"qty_avg_price":"0","unit_quantity":1,"total_quantity":1,"price_detail":[]}}`, meta, price)
+	})
+	defer done()
+
+	for _, setNo := range []string{"1234", "1234-", "-1", "1234-x", "1234-0"} {
+		if _, err := c.PartOutValue(setNo, nil); err == nil {
+			t.Errorf("expected error for set number %q without variant", setNo)
+		}
+	}
+	p, err := c.PartOutValue("1234-1", &PartOutOptions{
+		GuideType:    GuideTypeSold,
+		NewOrUsed:    NewOrUsedUsed,
+		CurrencyCode: "EUR",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SetNo != "1234-1" || p.CurrencyCode != "EUR" {
+		t.Errorf("got set %q in %q", p.SetNo, p.CurrencyCode)
+	}
+	if len(p.Entries) != 6 {
+		t.Errorf("got %d entries, want 6", len(p.Entries))
+	}
+	if guideRequests != 6 {
+		t.Errorf("got %d price guide requests, want 6", guideRequests)
+	}
+	checks := []struct {
+		name string
+		got  Amount
+		want string
+	}{
+		{"parts", p.PartsValue, "3.60"},
+		{"minifigs", p.MinifigsValue, "12.00"},
+		{"extras", p.ExtrasValue, "0.50"},
+		{"alternates", p.AlternatesValue, "0.60"},
+		{"counterparts", p.CounterpartsValue, "0.50"},
+		{"total", p.Total(), "15.60"},
+		{"total with extras", p.TotalWithExtras(), "16.10"},
+		{"set price", p.SetPrice(), "20.00"},
+	}
+	for _, check := range checks {
+		if got := check.got.String(); got != check.want {
+			t.Errorf("%s = %s, want %s", check.name, got, check.want)
+		}
+	}
+	if r := p.Ratio(); math.Abs(r-0.78) > 1e-9 {
+		t.Errorf("ratio = %v, want 0.78", r)
+	}
+	if p.Entries[2].MatchNo != 1 || !p.Entries[3].IsAlternate {
+		t.Errorf("alternate group not preserved: %+v", p.Entries[2:4])
+	}
+}
@@ -0,0 +1,149 @@
+package bricklinkstore
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/internal/pool"
+)
+
+const (
+	defaultWorkers       = 4
+	defaultDelay         = 200 * time.Millisecond
+	DefaultPriceGuideTTL = time.Hour
+)
+
+// SetConcurrency sets the number of requests GetPriceGuides and the Warm
+// methods make in parallel and the minimum delay between the starts of
+// their requests. Other requests are not throttled.
+func (c *Client) SetConcurrency(workers int, delay time.Duration) {
+	if workers < 1 {
+		workers = 1
+	}
+	c.workers = workers
+	c.throttle = pool.NewThrottle(delay)
+}
+
+// SetPriceGuideTTL sets how long GetPriceGuides serves price guides from its
+// cache and empties it. A TTL of 0 disables the cache.
+func (c *Client) SetPriceGuideTTL(ttl time.Duration) {
+	c.priceGuides = newPriceGuideCache(ttl)
+}
+
+// PriceGuideRequest identifies a price guide to retrieve.
+type PriceGuideRequest struct {
+	ItemType ItemType
+	ItemNo   string
+	Options  PriceGuideOptions
+}
+
+func (r PriceGuideRequest) url() string {
+	return fmt.Sprintf("/items/%s/%s/price%s", r.ItemType, r.ItemNo, toParams(&r.Options))
+}
+
+// PriceGuideResult is the result of a request in GetPriceGuides.
+type PriceGuideResult struct {
+	Request    PriceGuideRequest
+	PriceGuide *PriceGuide
+	Err        error
+	Cached     bool // Whether the price guide was served from the cache
+}
+
+// GetPriceGuides retrieves many price guides with a bounded number of
+// parallel requests. Results are in the order of requests. Requests for
+// the same price guide are made once and a failed request does not stop
+// the others. Price guides retrieved within the TTL are served from the
+// cache; failures are not cached.
+func (c *Client) GetPriceGuides(requests []PriceGuideRequest) []PriceGuideResult {
+	results := make([]PriceGuideResult, len(requests))
+	var unique []string
+	indexes := make(map[string][]int)
+	for i, r := range requests {
+		results[i].Request = r
+		u := r.url()
+		if guide, ok := c.priceGuides.get(u); ok {
+			results[i].PriceGuide, results[i].Cached = guide, true
+			continue
+		}
+		if _, ok := indexes[u]; !ok {
+			unique = append(unique, u)
+		}
+		indexes[u] = append(indexes[u], i)
+	}
+
+	c.run(len(unique), func(j int) {
+		u := unique[j]
+		var r priceGuideResponse
+		err := c.doGet(u, &r)
+		if err == nil {
+			err = checkMeta(r.Meta)
+		}
+		var guide *PriceGuide
+		if err == nil {
+			guide = &r.Data
+			c.priceGuides.put(u, guide)
+		}
+		for _, i := range indexes[u] {
+			results[i].PriceGuide, results[i].Err = guide, err
+		}
+	})
+	return results
+}
+
+// priceGuideCache holds price guides by request URL until they expire.
+type priceGuideCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]priceGuideEntry
+}
+
+type priceGuideEntry struct {
+	guide   *PriceGuide
+	expires time.Time
+}
+
+func newPriceGuideCache(ttl time.Duration) *priceGuideCache {
+	return &priceGuideCache{ttl: ttl, entries: make(map[string]priceGuideEntry)}
+}
+
+func (pc *priceGuideCache) get(url string) (*PriceGuide, bool) {
+	if pc == nil {
+		return nil, false
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	e, ok := pc.entries[url]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(pc.entries, url)
+		return nil, false
+	}
+	return e.guide, true
+}
+
+func (pc *priceGuideCache) put(url string, guide *PriceGuide) {
+	if pc == nil {
+		return
+	}
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if pc.ttl > 0 {
+		pc.entries[url] = priceGuideEntry{guide, time.Now().Add(pc.ttl)}
+	}
+}
+
+// run calls do for each index from 0 to n-1 in the worker pool of the
+// client, waiting for the throttle before each call.
+func (c *Client) run(n int, do func(i int)) {
+	workers := c.workers
+	if workers < 1 {
+		workers = defaultWorkers
+	}
+	pool.Run(n, workers, func(i int) {
+		c.throttle.Wait(c.base)
+		do(i)
+	})
+}
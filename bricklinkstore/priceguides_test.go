@@ -0,0 +1,98 @@
+package bricklinkstore
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestToParams(t *testing.T) {
+	got := toParams(&PriceGuideOptions{ColorID: 11, GuideType: GuideTypeSold, NewOrUsed: NewOrUsedNew, CurrencyCode: "USD"})
+	want := "?color_id=11&currency_code=USD&guide_type=sold&new_or_used=N"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := toParams(&PriceGuideOptions{}); got != "" {
+		t.Errorf("got %q for empty options", got)
+	}
+}
+
+func TestGetPriceGuides(t *testing.T) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	c, done := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		counts[r.URL.String()]++
+		mu.Unlock()
+		if r.URL.Path == "/items/PART/bad/price" {
+			fmt.Fprint(w, `{"meta":{"description":"not found","message":"RESOURCE_NOT_FOUND","code":404},"data":{}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"meta":{"description":"OK","message":"OK","code":200},"data":{"item":{"no":"3001","type":"PART"},"new_or_used":"N","currency_code":"USD","min_price":"0","max_price":"0","avg_price":"0.25","qty_avg_price":"0","unit_quantity":1,"total_quantity":1,"price_detail":[]}}`)
+	})
+	defer done()
+	c.SetConcurrency(3, time.Millisecond)
+	c.SetPriceGuideTTL(time.Hour)
+
+	red := PriceGuideRequest{ItemType: ItemTypePart, ItemNo: "3001", Options: PriceGuideOptions{ColorID: 5}}
+	blue := PriceGuideRequest{ItemType: ItemTypePart, ItemNo: "3001", Options: PriceGuideOptions{ColorID: 7}}
+	bad := PriceGuideRequest{ItemType: ItemTypePart, ItemNo: "bad"}
+	results := c.GetPriceGuides([]PriceGuideRequest{red, blue, red, bad})
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for i, r := range results[:3] {
+		if r.Err != nil || r.PriceGuide == nil || r.PriceGuide.AvgPrice.String() != "0.25" || r.Cached {
+			t.Errorf("result %d: unexpected %+v", i, r)
+		}
+	}
+	if results[3].Err == nil || results[3].PriceGuide != nil {
+		t.Errorf("expected error for unknown item, got %+v", results[3])
+	}
+	if results[2].Request != red {
+		t.Errorf("result not in request order: %+v", results[2].Request)
+	}
+
+	results = c.GetPriceGuides([]PriceGuideRequest{blue, bad})
+	if !results[0].Cached || results[0].Err != nil {
+		t.Errorf("expected cached result, got %+v", results[0])
+	}
+	if results[1].Cached || results[1].Err == nil {
+		t.Errorf("expected failure not to be cached, got %+v", results[1])
+	}
+	for u, n := range counts {
+		want := 1
+		if u == "/items/PART/bad/price" {
+			want = 2
+		}
+		if n != want {
+			t.Errorf("%s requested %d times, want %d", u, n, want)
+		}
+	}
+	if len(counts) != 3 {
+		t.Errorf("got %d distinct requests, want 3", len(counts))
+	}
+
+	// Only the worker pools are throttled.
+	c.SetConcurrency(1, time.Hour)
+	finished := make(chan error)
+	go func() {
+		for i := 0; i < 2; i++ {
+			if _, err := c.GetPriceGuide(ItemTypePart, "3001", nil); err != nil {
+				finished <- err
+				return
+			}
+		}
+		finished <- nil
+	}()
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("single requests were throttled")
+	}
+}
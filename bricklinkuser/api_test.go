@@ -1,16 +1,20 @@
 package bricklinkuser
 
 import (
-	"io/ioutil"
-	"net/http"
-	"strings"
+	"encoding/json"
 	"testing"
 )
 
-func TestGetError(t *testing.T) {
+func TestCheckResponse(t *testing.T) {
 	jsonText := `{"returnCode":-3,"returnMessage":"Invalid Parameter!","errorTicket":0,"procssingTime":0}`
-	r := &http.Response{Body: ioutil.NopCloser(strings.NewReader(jsonText))}
-	if err := getError(r, nil); err != nil && err.Error() != "Invalid Parameter!" {
+	var r loginAndOutResult
+	if err := json.Unmarshal([]byte(jsonText), &r); err != nil {
 		t.Fatal(err)
 	}
+	if err := checkResponse(r.ReturnCode, r.ReturnMessage); err == nil || err.Error() != "return code -3 Invalid Parameter!" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := checkResponse(0, ""); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
 }
@@ -1,11 +1,16 @@
 package bricklinkuser
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
 
 // https://www.bricklink.com/ajax/clone/search/searchproduct.ajax?q=75159&st=0&cond=&brand=1000&type=&cat=&yf=0&yt=0&loc=&reg=0&ca=0&ss=&pmt=&nmp=0&color=-1&min=0&max=0&minqty=0&nosuperlot=1&incomplete=0&showempty=1&rpp=25&pi=1&ci=0
 
-func (c *Client) SearchProduct() (*SearchProduct, error) {
-	url := fmt.Sprintf("https://%s/ajax/clone/search/searchproduct.ajax", getHost("www")) // params TBD
+// SearchProduct searches the lots for sale in all stores.
+func (c *Client) SearchProduct(options *SearchProductOptions) (*SearchProduct, error) {
+	url := fmt.Sprintf("https://%s/ajax/clone/search/searchproduct.ajax?%s", getHost("www"), options.values().Encode())
 	var r SearchProduct
 	if err := c.doGet(url, &r); err != nil {
 		return nil, err
@@ -13,6 +18,46 @@ func (c *Client) SearchProduct() (*SearchProduct, error) {
 	return &r, checkResponse(r.ReturnCode, r.ReturnMessage)
 }
 
+// AllColors matches lots in any color in SearchProductOptions.
+const AllColors = -1
+
+// SearchProductOptions contains the parameters of SearchProduct. A nil
+// value searches everything.
+type SearchProductOptions struct {
+	Query          string    // Item number or name
+	ItemType       ItemType  // "" for all types
+	Condition      NewOrUsed // "" for either
+	ColorID        int       // Color ID, or AllColors
+	MinQuantity    int       // Minimum lot quantity
+	Location       string    // Seller country code, "" for anywhere
+	NoSuperLots    bool      // Exclude superlots
+	ResultsPerPage int       // Defaults to 25
+	Page           int       // 1-based page index
+}
+
+func (o *SearchProductOptions) values() url.Values {
+	if o == nil {
+		o = &SearchProductOptions{ColorID: AllColors}
+	}
+	params := url.Values{}
+	params.Set("q", o.Query)
+	params.Set("type", string(o.ItemType))
+	params.Set("cond", string(o.Condition))
+	params.Set("color", strconv.Itoa(o.ColorID))
+	params.Set("minqty", strconv.Itoa(o.MinQuantity))
+	params.Set("loc", o.Location)
+	if o.NoSuperLots {
+		params.Set("nosuperlot", "1")
+	}
+	if o.ResultsPerPage != 0 {
+		params.Set("rpp", strconv.Itoa(o.ResultsPerPage))
+	}
+	if o.Page != 0 {
+		params.Set("pi", strconv.Itoa(o.Page))
+	}
+	return params
+}
+
 type SearchProduct struct {
 	TotalCount     int           `json:"total_count"`
 	ColorID        int           `json:"idColor"`
@@ -27,6 +72,10 @@ type SearchProduct struct {
 
 type ProductList struct {
 	InvID                  int          `json:"idInv"`
+	ItemID                 int          `json:"idItem"`      // Not confirmed to be in json
+	ItemType               ItemType     `json:"typeItem"`    // Not confirmed to be in json
+	ItemNumber             string       `json:"strItemNo"`   // Not confirmed to be in json
+	ItemName               string       `json:"strItemName"` // Not confirmed to be in json
 	Description            string       `json:"strDesc"`
 	NewOrUsed              NewOrUsed    `json:"codeNew"`
 	Completeness           Completeness `json:"codeComplete"`
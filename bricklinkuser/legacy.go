@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Converted from https://static.bricklink.com/_cache/jslegacy.2ec6747ecd9c6e44b6ac7e545e3f0457.js on 2019-05-25
@@ -349,50 +350,14 @@ type PackageRestriction struct {
 
 // blc_CatalogItem.getItemTypeName
 func getItemTypeName(itemType rune, isPlural bool) string {
-	switch itemType {
-	case 'P', 'p':
-		if isPlural {
-			return "Parts"
+	itemType = unicode.ToUpper(itemType)
+	for _, n := range itemTypeNameList {
+		if n.Type == itemType {
+			if isPlural {
+				return n.Plural
+			}
+			return n.Singular
 		}
-		return "Part"
-	case 'S', 's':
-		if isPlural {
-			return "Sets"
-		}
-		return "Set"
-	case 'M', 'm':
-		if isPlural {
-			return "Minifigs"
-		}
-		return "Minifig"
-	case 'G', 'g':
-		return "Gear"
-	case 'I', 'i':
-		if isPlural {
-			return "Instructions"
-		}
-		return "Instruction"
-	case 'O', 'o':
-		if isPlural {
-			return "Original Boxes"
-		}
-		return "Original Box"
-	case 'C', 'c':
-		if isPlural {
-			return "Catalogs"
-		}
-		return "Catalog"
-	case 'B', 'b':
-		if isPlural {
-			return "Books"
-		}
-		return "Book"
-	case 'U', 'u':
-		if isPlural {
-			return "Custom Items"
-		}
-		return "Custom Item"
-	default:
-		return ""
 	}
+	return ""
 }
@@ -0,0 +1,442 @@
+package bricklinkuser
+
+// To regenerate lookups.go, set $BRICKLINK_ALLVARS and $BRICKLINK_JSLEGACY to
+// saved copies of allVars.js and the jslegacy bundle and run go generate.
+//go:generate go run ../cmd/genlookups -o lookups.go
+
+import (
+	"strings"
+	"sync"
+)
+
+// Indexes over the tables in lookups.go. They are built once on first use so
+// that lookups are constant time and never touch the network. When two
+// entries fold to the same name, the name indexes keep the first in table
+// order.
+var (
+	indexOnce sync.Once
+
+	currenciesByID       map[int]*Currency
+	currenciesByCode     map[string]*Currency
+	currenciesByName     map[string]*Currency
+	paymentMethodsByID   map[int]*PaymentMethod
+	paymentMethodsByName map[string]*PaymentMethod
+	countriesByID        map[string]*Country
+	countriesByName      map[string]*Country
+	languagesByCode      map[string]*Language
+	languagesByName      map[string]*Language
+	promosByID           map[int]*Promo
+	promosByName         map[string]*Promo
+	categoriesByID       map[int]*Category
+	categoriesByName     map[string]*Category
+	categoriesByBrand    map[int][]Category
+	categoriesByType     map[ItemType][]Category
+	colorsByID           map[int]*Color
+	colorsByName         map[string]*Color
+	brandsByID           map[int]*Brand
+	brandsByName         map[string]*Brand
+	years                map[int]bool
+)
+
+func buildIndexes() {
+	currenciesByID = make(map[int]*Currency, len(currencyList))
+	currenciesByCode = make(map[string]*Currency, len(currencyList))
+	currenciesByName = make(map[string]*Currency, len(currencyList))
+	for i := range currencyList {
+		c := &currencyList[i]
+		currenciesByID[c.CurrencyID] = c
+		currenciesByCode[strings.ToUpper(c.CurrencyCode)] = c
+		if _, ok := currenciesByName[foldName(c.CurrencyName)]; !ok {
+			currenciesByName[foldName(c.CurrencyName)] = c
+		}
+	}
+
+	paymentMethodsByID = make(map[int]*PaymentMethod, len(paymentMethodList))
+	paymentMethodsByName = make(map[string]*PaymentMethod, len(paymentMethodList))
+	for i := range paymentMethodList {
+		p := &paymentMethodList[i]
+		paymentMethodsByID[p.PaymentMethodID] = p
+		if _, ok := paymentMethodsByName[foldName(p.MethodName)]; !ok {
+			paymentMethodsByName[foldName(p.MethodName)] = p
+		}
+	}
+
+	countriesByID = make(map[string]*Country, len(countryList))
+	countriesByName = make(map[string]*Country, len(countryList))
+	for i := range countryList {
+		c := &countryList[i]
+		countriesByID[strings.ToUpper(c.CountryID)] = c
+		if _, ok := countriesByName[foldName(c.CountryName)]; !ok {
+			countriesByName[foldName(c.CountryName)] = c
+		}
+	}
+
+	languagesByCode = make(map[string]*Language, len(languageList))
+	languagesByName = make(map[string]*Language, len(languageList))
+	for i := range languageList {
+		l := &languageList[i]
+		languagesByCode[strings.ToUpper(l.LanguageCode)] = l
+		if _, ok := languagesByName[foldName(l.LanguageName)]; !ok {
+			languagesByName[foldName(l.LanguageName)] = l
+		}
+	}
+
+	promosByID = make(map[int]*Promo, len(promoList))
+	promosByName = make(map[string]*Promo, len(promoList))
+	for i := range promoList {
+		p := &promoList[i]
+		promosByID[p.PromoID] = p
+		if _, ok := promosByName[foldName(p.PromoName)]; !ok {
+			promosByName[foldName(p.PromoName)] = p
+		}
+	}
+
+	categoriesByID = make(map[int]*Category, len(categoryList))
+	categoriesByName = make(map[string]*Category, len(categoryList))
+	categoriesByBrand = make(map[int][]Category)
+	categoriesByType = make(map[ItemType][]Category)
+	for i := range categoryList {
+		c := &categoryList[i]
+		categoriesByID[c.CategoryID] = c
+		if _, ok := categoriesByName[foldName(c.CategoryName)]; !ok {
+			categoriesByName[foldName(c.CategoryName)] = c
+		}
+		categoriesByBrand[c.BrandID] = append(categoriesByBrand[c.BrandID], *c)
+		for _, t := range c.Types {
+			itemType := ItemType(t)
+			categoriesByType[itemType] = append(categoriesByType[itemType], *c)
+		}
+	}
+
+	colorsByID = make(map[int]*Color, len(colorList))
+	colorsByName = make(map[string]*Color, len(colorList))
+	for i := range colorList {
+		c := &colorList[i]
+		colorsByID[c.ColorID] = c
+		if _, ok := colorsByName[foldName(c.ColorName)]; !ok {
+			colorsByName[foldName(c.ColorName)] = c
+		}
+	}
+
+	brandsByID = make(map[int]*Brand, len(brandList))
+	brandsByName = make(map[string]*Brand, len(brandList))
+	for i := range brandList {
+		b := &brandList[i]
+		brandsByID[b.BrandID] = b
+		if _, ok := brandsByName[foldName(b.BrandName)]; !ok {
+			brandsByName[foldName(b.BrandName)] = b
+		}
+	}
+
+	years = make(map[int]bool, len(yearList))
+	for _, y := range yearList {
+		years[y] = true
+	}
+}
+
+func index() {
+	indexOnce.Do(buildIndexes)
+}
+
+// foldName normalizes a name for case-insensitive comparison.
+func foldName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// matchName reports whether name contains query, ignoring case.
+func matchName(name, query string) bool {
+	return strings.Contains(strings.ToLower(name), foldName(query))
+}
+
+// Currencies returns all currencies supported by BrickLink.
+func Currencies() []Currency {
+	return append([]Currency(nil), currencyList...)
+}
+
+// GetCurrencyByID retrieves currency information for an id.
+func GetCurrencyByID(currencyID int) *Currency {
+	index()
+	return currenciesByID[currencyID]
+}
+
+// GetCurrencyByCode retrieves currency information for an ISO 4217 code.
+func GetCurrencyByCode(currencyCode string) *Currency {
+	index()
+	return currenciesByCode[strings.ToUpper(currencyCode)]
+}
+
+// GetCurrencyByName retrieves currency information for a name, ignoring case.
+func GetCurrencyByName(name string) *Currency {
+	index()
+	return currenciesByName[foldName(name)]
+}
+
+// SearchCurrencies returns the currencies with names or codes containing query, ignoring case.
+func SearchCurrencies(query string) []Currency {
+	var currencies []Currency
+	for _, c := range currencyList {
+		if matchName(c.CurrencyName, query) || matchName(c.CurrencyCode, query) {
+			currencies = append(currencies, c)
+		}
+	}
+	return currencies
+}
+
+// PaymentMethods returns all payment methods known to BrickLink.
+func PaymentMethods() []PaymentMethod {
+	return append([]PaymentMethod(nil), paymentMethodList...)
+}
+
+// GetPaymentMethodByID retrieves payment method information for an id.
+func GetPaymentMethodByID(paymentMethodID int) *PaymentMethod {
+	index()
+	return paymentMethodsByID[paymentMethodID]
+}
+
+// GetPaymentMethodByName retrieves payment method information for a name, ignoring case.
+func GetPaymentMethodByName(name string) *PaymentMethod {
+	index()
+	return paymentMethodsByName[foldName(name)]
+}
+
+// SearchPaymentMethods returns the payment methods with names containing query, ignoring case.
+func SearchPaymentMethods(query string) []PaymentMethod {
+	var methods []PaymentMethod
+	for _, p := range paymentMethodList {
+		if matchName(p.MethodName, query) {
+			methods = append(methods, p)
+		}
+	}
+	return methods
+}
+
+// Countries returns all countries known to BrickLink.
+func Countries() []Country {
+	return append([]Country(nil), countryList...)
+}
+
+// GetCountryByID retrieves country information for an id.
+// Converted from part of blUtil.getCountryName in jslegacy.
+func GetCountryByID(countryID string) *Country {
+	index()
+	return countriesByID[strings.ToUpper(countryID)]
+}
+
+// GetCountryByName retrieves country information for a name, ignoring case.
+func GetCountryByName(name string) *Country {
+	index()
+	return countriesByName[foldName(name)]
+}
+
+// GetCountryName retrieves the name of a country for an id.
+// Converted from blUtil.getCountryName in jslegacy.
+func GetCountryName(countryID string) string {
+	if country := GetCountryByID(countryID); country != nil {
+		return country.CountryName
+	}
+	return "Unknown"
+}
+
+// SearchCountries returns the countries with names or ids containing query, ignoring case.
+func SearchCountries(query string) []Country {
+	var countries []Country
+	for _, c := range countryList {
+		if matchName(c.CountryName, query) || matchName(c.CountryID, query) {
+			countries = append(countries, c)
+		}
+	}
+	return countries
+}
+
+// Languages returns all languages known to BrickLink.
+func Languages() []Language {
+	return append([]Language(nil), languageList...)
+}
+
+// GetLanguageByCode retrieves language information for a code.
+func GetLanguageByCode(languageCode string) *Language {
+	index()
+	return languagesByCode[strings.ToUpper(languageCode)]
+}
+
+// GetLanguageByName retrieves language information for a name, ignoring case.
+func GetLanguageByName(name string) *Language {
+	index()
+	return languagesByName[foldName(name)]
+}
+
+// SearchLanguages returns the languages with names or codes containing query, ignoring case.
+func SearchLanguages(query string) []Language {
+	var languages []Language
+	for _, l := range languageList {
+		if matchName(l.LanguageName, query) || matchName(l.LanguageCode, query) {
+			languages = append(languages, l)
+		}
+	}
+	return languages
+}
+
+// Promos returns all promotional brands known to BrickLink.
+func Promos() []Promo {
+	return append([]Promo(nil), promoList...)
+}
+
+// GetPromoByID retrieves promo information for an id.
+func GetPromoByID(promoID int) *Promo {
+	index()
+	return promosByID[promoID]
+}
+
+// GetPromoByName retrieves promo information for a name, ignoring case.
+func GetPromoByName(name string) *Promo {
+	index()
+	return promosByName[foldName(name)]
+}
+
+// SearchPromos returns the promos with names containing query, ignoring case.
+func SearchPromos(query string) []Promo {
+	var promos []Promo
+	for _, p := range promoList {
+		if matchName(p.PromoName, query) {
+			promos = append(promos, p)
+		}
+	}
+	return promos
+}
+
+// AllCategories returns all catalog categories regardless of item type.
+func AllCategories() []Category {
+	return append([]Category(nil), categoryList...)
+}
+
+// Categories returns the catalog categories that contain items of the given
+// type, as listed in Category.Types.
+func Categories(itemType ItemType) []Category {
+	index()
+	return append([]Category(nil), categoriesByType[itemType]...)
+}
+
+// GetCategoryByID retrieves category information for an id.
+func GetCategoryByID(categoryID int) *Category {
+	index()
+	return categoriesByID[categoryID]
+}
+
+// GetCategoryByName retrieves category information for a name, ignoring case.
+// When several categories share a name, the first in catalog order is returned.
+func GetCategoryByName(name string) *Category {
+	index()
+	return categoriesByName[foldName(name)]
+}
+
+// SearchCategories returns the categories with names containing query, ignoring case.
+func SearchCategories(query string) []Category {
+	var categories []Category
+	for _, c := range categoryList {
+		if matchName(c.CategoryName, query) {
+			categories = append(categories, c)
+		}
+	}
+	return categories
+}
+
+// HasType reports whether the category contains items of the given type.
+func (c *Category) HasType(itemType ItemType) bool {
+	return len(itemType) == 1 && strings.Contains(c.Types, string(itemType))
+}
+
+// Brand returns the brand of the category or nil if it is unknown.
+func (c *Category) Brand() *Brand {
+	return GetBrandByID(c.BrandID)
+}
+
+// Colors returns all colors in the BrickLink catalog.
+func Colors() []Color {
+	return append([]Color(nil), colorList...)
+}
+
+// GetColorByID retrieves color information for an id.
+// Converted from blUtil.getColorInst in jslegacy.
+func GetColorByID(colorID int) *Color {
+	index()
+	return colorsByID[colorID]
+}
+
+// GetColorByName retrieves color information for a name, ignoring case.
+func GetColorByName(name string) *Color {
+	index()
+	return colorsByName[foldName(name)]
+}
+
+// GetColorName retrieves the name of a color for an id.
+// Converted from blUtil.getColorName in jslegacy.
+func GetColorName(colorID int) string {
+	if color := GetColorByID(colorID); color != nil {
+		return color.ColorName
+	}
+	return "Unknown"
+}
+
+// SearchColors returns the colors with names containing query, ignoring case.
+func SearchColors(query string) []Color {
+	var colors []Color
+	for _, c := range colorList {
+		if matchName(c.ColorName, query) {
+			colors = append(colors, c)
+		}
+	}
+	return colors
+}
+
+// Years returns the catalog release years, newest first.
+func Years() []int {
+	return append([]int(nil), yearList...)
+}
+
+// IsCatalogYear reports whether year is a release year in the catalog.
+func IsCatalogYear(year int) bool {
+	index()
+	return years[year]
+}
+
+// Brands returns all item brands. Categories and items refer to a brand by
+// BrandID, also sent as itemBrand and the brand search parameter.
+func Brands() []Brand {
+	return append([]Brand(nil), brandList...)
+}
+
+// GetBrandByID retrieves brand information for an id.
+func GetBrandByID(brandID int) *Brand {
+	index()
+	return brandsByID[brandID]
+}
+
+// GetBrandByName retrieves brand information for a name, ignoring case.
+func GetBrandByName(name string) *Brand {
+	index()
+	return brandsByName[foldName(name)]
+}
+
+// CategoriesByBrand returns the categories that belong to a brand.
+func CategoriesByBrand(brandID int) []Category {
+	index()
+	return append([]Category(nil), categoriesByBrand[brandID]...)
+}
+
+// Name returns the singular name of an item type, such as "Minifig", or ""
+// if the type is unknown.
+func (t ItemType) Name() string {
+	if len(t) != 1 {
+		return ""
+	}
+	return getItemTypeName(rune(t[0]), false)
+}
+
+// PluralName returns the plural name of an item type, such as "Minifigs",
+// or "" if the type is unknown.
+func (t ItemType) PluralName() string {
+	if len(t) != 1 {
+		return ""
+	}
+	return getItemTypeName(rune(t[0]), true)
+}
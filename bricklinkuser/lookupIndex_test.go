@@ -0,0 +1,69 @@
+package bricklinkuser
+
+import "testing"
+
+func TestLookupByID(t *testing.T) {
+	if c := GetCurrencyByCode("nok"); c == nil || c.CurrencyID != 106 {
+		t.Errorf("unexpected currency for NOK: %v", c)
+	}
+	if c := GetCountryByID("uk"); c == nil || c.CountryName != "United Kingdom" {
+		t.Errorf("unexpected country for UK: %v", c)
+	}
+	if name := GetColorName(11); name != "Black" {
+		t.Errorf("expected Black, but got %s", name)
+	}
+	if name := GetColorName(-1); name != "Unknown" {
+		t.Errorf("expected Unknown, but got %s", name)
+	}
+	if l := GetLanguageByCode("DE"); l == nil || l.LanguageName != "German" {
+		t.Errorf("unexpected language for DE: %v", l)
+	}
+}
+
+func TestLookupByName(t *testing.T) {
+	if c := GetColorByName("dark bluish gray"); c == nil || c.ColorID != 85 {
+		t.Errorf("unexpected color for dark bluish gray: %v", c)
+	}
+	if p := GetPaymentMethodByName("PAYPAL"); p == nil || p.PaymentMethodID != 11 {
+		t.Errorf("unexpected payment method for PAYPAL: %v", p)
+	}
+	if c := GetCountryByName("usa"); c == nil || c.CountryID != "US" {
+		t.Errorf("unexpected country for usa: %v", c)
+	}
+	if c := GetCategoryByName("No Such Category"); c != nil {
+		t.Errorf("expected no category, but got %v", c)
+	}
+}
+
+func TestSearchColors(t *testing.T) {
+	colors := SearchColors("BLUISH")
+	if len(colors) == 0 {
+		t.Fatal("expected bluish colors, but got none")
+	}
+	for _, c := range colors {
+		if !matchName(c.ColorName, "bluish") {
+			t.Errorf("unexpected color %s", c.ColorName)
+		}
+	}
+}
+
+func TestCategories(t *testing.T) {
+	parts := Categories(ItemTypePart)
+	if len(parts) == 0 {
+		t.Fatal("expected part categories, but got none")
+	}
+	for _, c := range parts {
+		if !c.HasType(ItemTypePart) {
+			t.Errorf("category %d %s does not contain parts", c.CategoryID, c.CategoryName)
+		}
+	}
+	brickArms := CategoriesByBrand(1001)
+	if len(brickArms) == 0 {
+		t.Fatal("expected BrickArms categories, but got none")
+	}
+	for _, c := range brickArms {
+		if b := c.Brand(); b == nil || b.BrandName != "BrickArms" {
+			t.Errorf("unexpected brand for %s: %v", c.CategoryName, b)
+		}
+	}
+}
@@ -1,3 +1,5 @@
+// Code generated by genlookups from allVars.js and jslegacy. DO NOT EDIT.
+
 package bricklinkuser
 
 // Converted from https://www.bricklink.com/js/allVars.js on 2019-05-25
@@ -312,28 +314,6 @@ var countryList = []Country{
 	{CountryID: "ZW", CountryName: "Zimbabwe"},
 }
 
-var countriesByID map[string]*Country
-
-// GetCountryByID retrieves country information for an id.
-// Converted from part of blUtil.getCountryName in jslegacy.
-func GetCountryByID(countryID string) *Country {
-	if len(countriesByID) == 0 {
-		for i := range countryList {
-			countriesByID[countryList[i].CountryID] = &countryList[i]
-		}
-	}
-	return countriesByID[countryID]
-}
-
-// GetCountryName retrieves the name of a country for an id.
-// Converted from blUtil.getCountryName in jslegacy.
-func GetCountryName(countryID string) string {
-	if country := GetCountryByID(countryID); country != nil {
-		return country.CountryName
-	}
-	return "Unknown"
-}
-
 type Language struct {
 	LanguageCode string `json:"codeLanguage"`
 	LanguageName string `json:"strLanguageName"`
@@ -1752,26 +1732,15 @@ var colorList = []Color{
 	{ColorID: 202, ColorName: "BA White Rubber", Group: 11, RGB: "D0D0D0"},
 }
 
-var colorsByID map[int]*Color
-
-// GetColorByID retrieves color information for an id.
-// Converted from blUtil.getColorInst in jslegacy.
-func GetColorByID(colorID int) *Color {
-	if len(colorsByID) == 0 {
-		for i := range colorList {
-			colorsByID[colorList[i].ColorID] = &colorList[i]
-		}
-	}
-	return colorsByID[colorID]
+type Brand struct {
+	BrandID   int    `json:"idBrand"`
+	BrandName string `json:"strBrandName"`
 }
 
-// GetColorName retrieves the name of a color for an id.
-// Converted from blUtil.getColorName in jslegacy.
-func GetColorName(colorID int) string {
-	if color := GetColorByID(colorID); color != nil {
-		return color.ColorName
-	}
-	return "Unknown"
+// Brands referenced by idBrand in _varArrayCategory in allVars.js
+var brandList = []Brand{
+	{BrandID: 1000, BrandName: "LEGO"},
+	{BrandID: 1001, BrandName: "BrickArms"},
 }
 
 // Converted from _varYearList in allVars.js
@@ -1850,3 +1819,22 @@ var yearList = []int{
 	1948,
 	1935,
 }
+
+type itemTypeName struct {
+	Type     rune
+	Singular string
+	Plural   string
+}
+
+// Converted from blc_CatalogItem.getItemTypeName in jslegacy
+var itemTypeNameList = []itemTypeName{
+	{Type: 'P', Singular: "Part", Plural: "Parts"},
+	{Type: 'S', Singular: "Set", Plural: "Sets"},
+	{Type: 'M', Singular: "Minifig", Plural: "Minifigs"},
+	{Type: 'G', Singular: "Gear", Plural: "Gear"},
+	{Type: 'I', Singular: "Instruction", Plural: "Instructions"},
+	{Type: 'O', Singular: "Original Box", Plural: "Original Boxes"},
+	{Type: 'C', Singular: "Catalog", Plural: "Catalogs"},
+	{Type: 'B', Singular: "Book", Plural: "Books"},
+	{Type: 'U', Singular: "Custom Item", Plural: "Custom Items"},
+}
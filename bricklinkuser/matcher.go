@@ -0,0 +1,268 @@
+package bricklinkuser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListConventions classify wanted lists by name prefix. Source lists hold
+// parts already owned, such as loose parts, that can fill the other lists.
+type ListConventions struct {
+	SourcePrefixes []string // Prefixes of source lists
+	IgnorePrefixes []string // Prefixes of lists that are neither wanted nor sources
+}
+
+// DefaultListConventions treats lists named "[LOOSE] ..." as sources and
+// skips lists named "[IGNORE] ...".
+var DefaultListConventions = ListConventions{
+	SourcePrefixes: []string{"[LOOSE]"},
+	IgnorePrefixes: []string{"[IGNORE]"},
+}
+
+// ListRole is how a wanted list takes part in matching.
+type ListRole int
+
+// Roles of wanted lists.
+const (
+	RoleWanted ListRole = iota
+	RoleSource
+	RoleIgnored
+)
+
+// Role classifies a wanted list by its name.
+func (lc ListConventions) Role(name string) ListRole {
+	if hasAnyPrefix(name, lc.SourcePrefixes) {
+		return RoleSource
+	}
+	if hasAnyPrefix(name, lc.IgnorePrefixes) {
+		return RoleIgnored
+	}
+	return RoleWanted
+}
+
+// TrimName removes a convention prefix from a list name.
+func (lc ListConventions) TrimName(name string) string {
+	for _, prefixes := range [][]string{lc.SourcePrefixes, lc.IgnorePrefixes} {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				return strings.TrimSpace(strings.TrimPrefix(name, prefix))
+			}
+		}
+	}
+	return name
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAllWantedLists retrieves the default wanted list and every other
+// wanted list of the user.
+func (c *Client) GetAllWantedLists() ([]*WantedListResults, error) {
+	defaultList, err := c.GetWantedList(0)
+	if err != nil {
+		return nil, err
+	}
+	lists := []*WantedListResults{defaultList}
+	for _, l := range defaultList.WantedLists {
+		if l.ID == 0 {
+			continue
+		}
+		list, err := c.GetWantedList(l.ID)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+// Allocation is a quantity of an item in a source list assigned to fill a
+// wanted list.
+type Allocation struct {
+	ItemType       ItemType        `json:"itemType"`
+	ItemNumber     string          `json:"itemNo"`
+	ItemName       string          `json:"itemName"`
+	ColorID        int             `json:"colorID"`
+	ColorName      string          `json:"colorName"`
+	Condition      WantedCondition `json:"condition"` // Condition wanted
+	WantedID       int             `json:"wantedID"`
+	WantedListID   int             `json:"wantedListID"`
+	WantedListName string          `json:"wantedListName"`
+	SourceID       int             `json:"sourceID"` // WantedID of the item in the source list
+	SourceListID   int             `json:"sourceListID"`
+	SourceListName string          `json:"sourceListName"` // Name without the convention prefix
+	Quantity       int             `json:"quantity"`
+}
+
+type matchKey struct {
+	itemType   ItemType
+	itemNumber string
+	colorID    int
+}
+
+// MatchWantedLists allocates the items of the source lists to the
+// unfilled items of the wanted lists, classifying lists by conv. The
+// default wanted list, with ID 0, is always wanted whatever its name.
+func MatchWantedLists(lists []*WantedListResults, conv ListConventions) []Allocation {
+	var wanted, sources []WantedItem
+	for _, list := range lists {
+		role := conv.Role(list.WantedListInfo.Name)
+		if list.WantedListInfo.ID == 0 {
+			role = RoleWanted
+		}
+		switch role {
+		case RoleWanted:
+			wanted = append(wanted, list.WantedItems...)
+		case RoleSource:
+			sources = append(sources, list.WantedItems...)
+		}
+	}
+	allocs := MatchItems(wanted, sources)
+	for i := range allocs {
+		allocs[i].SourceListName = conv.TrimName(allocs[i].SourceListName)
+	}
+	return allocs
+}
+
+// MatchItems allocates source items to wanted items with the same item
+// type, number and color ID. A source fills a wanted item when either
+// condition is any or the conditions are equal. Quantities still needed
+// are filled greedily in list order. Allocations are sorted by item.
+func MatchItems(wanted, sources []WantedItem) []Allocation {
+	sourcesByKey := make(map[matchKey][]WantedItem)
+	for _, s := range sources {
+		k := keyOf(s)
+		sourcesByKey[k] = append(sourcesByKey[k], s)
+	}
+	remaining := make(map[int]int) // Remaining quantity by source WantedID
+	for _, s := range sources {
+		remaining[s.WantedID] = s.WantedQty
+	}
+
+	var allocs []Allocation
+	for _, w := range wanted {
+		need := w.WantedQty - w.WantedQtyFilled
+		for _, s := range sourcesByKey[keyOf(w)] {
+			if need <= 0 {
+				break
+			}
+			if !conditionMatches(w.WantedCondition, s.WantedCondition) {
+				continue
+			}
+			qty := remaining[s.WantedID]
+			if qty <= 0 {
+				continue
+			}
+			if qty > need {
+				qty = need
+			}
+			remaining[s.WantedID] -= qty
+			need -= qty
+			allocs = append(allocs, Allocation{
+				ItemType:       w.ItemType,
+				ItemNumber:     w.ItemNumber,
+				ItemName:       w.ItemName,
+				ColorID:        w.ColorID,
+				ColorName:      w.ColorName,
+				Condition:      w.WantedCondition,
+				WantedID:       w.WantedID,
+				WantedListID:   w.WantedListID,
+				WantedListName: w.WantedListName,
+				SourceID:       s.WantedID,
+				SourceListID:   s.WantedListID,
+				SourceListName: s.WantedListName,
+				Quantity:       qty,
+			})
+		}
+	}
+	sort.SliceStable(allocs, func(i, j int) bool {
+		a, b := allocs[i], allocs[j]
+		if a.ItemType != b.ItemType {
+			return a.ItemType < b.ItemType
+		}
+		if a.ItemNumber != b.ItemNumber {
+			return a.ItemNumber < b.ItemNumber
+		}
+		return a.ColorID < b.ColorID
+	})
+	return allocs
+}
+
+func conditionMatches(wanted, source WantedCondition) bool {
+	return wanted == WantedConditionAny || source == WantedConditionAny || wanted == source
+}
+
+func keyOf(item WantedItem) matchKey {
+	return matchKey{item.ItemType, item.ItemNumber, item.ColorID}
+}
+
+// WriteAllocationsText writes allocations grouped by item.
+func WriteAllocationsText(w io.Writer, allocs []Allocation) error {
+	for i, a := range allocs {
+		if i == 0 || keyOfAllocation(a) != keyOfAllocation(allocs[i-1]) {
+			if i != 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintln(w, a.ItemType, a.ColorName, a.ItemNumber, a.ItemName); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, " - %d from %s to %s\n", a.Quantity, a.SourceListName, a.WantedListName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func keyOfAllocation(a Allocation) matchKey {
+	return matchKey{a.ItemType, a.ItemNumber, a.ColorID}
+}
+
+// WriteAllocationsJSON writes allocations as a JSON array.
+func WriteAllocationsJSON(w io.Writer, allocs []Allocation) error {
+	if allocs == nil {
+		allocs = []Allocation{}
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(allocs)
+}
+
+var allocationCSVHeader = []string{
+	"itemType", "itemNo", "itemName", "colorID", "colorName", "condition",
+	"wantedID", "wantedListID", "wantedListName",
+	"sourceID", "sourceListID", "sourceListName", "quantity",
+}
+
+// WriteAllocationsCSV writes allocations as CSV with a header row.
+func WriteAllocationsCSV(w io.Writer, allocs []Allocation) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(allocationCSVHeader); err != nil {
+		return err
+	}
+	for _, a := range allocs {
+		record := []string{
+			string(a.ItemType), a.ItemNumber, a.ItemName, strconv.Itoa(a.ColorID), a.ColorName, string(a.Condition),
+			strconv.Itoa(a.WantedID), strconv.Itoa(a.WantedListID), a.WantedListName,
+			strconv.Itoa(a.SourceID), strconv.Itoa(a.SourceListID), a.SourceListName, strconv.Itoa(a.Quantity),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,71 @@
+package bricklinkuser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMatchWantedLists(t *testing.T) {
+	lists := []*WantedListResults{
+		{WantedListInfo: WantedListInfo{Name: "Castle", ID: 1}, WantedItems: []WantedItem{
+			{WantedID: 1, WantedListName: "Castle", ItemType: ItemTypePart, ItemNumber: "3024", ColorID: 86, ColorName: "Light Bluish Gray", WantedQty: 10, WantedQtyFilled: 2, WantedCondition: WantedConditionAny},
+			{WantedID: 2, WantedListName: "Castle", ItemType: ItemTypePart, ItemNumber: "3001", ColorID: 11, ColorName: "Black", WantedQty: 4, WantedCondition: WantedConditionNew},
+		}},
+		{WantedListInfo: WantedListInfo{Name: "Ship", ID: 2}, WantedItems: []WantedItem{
+			{WantedID: 3, WantedListName: "Ship", ItemType: ItemTypePart, ItemNumber: "3024", ColorID: 86, ColorName: "Light Bluish Gray", WantedQty: 5, WantedCondition: WantedConditionAny},
+		}},
+		{WantedListInfo: WantedListInfo{Name: "[IGNORE] Old", ID: 3}, WantedItems: []WantedItem{
+			{WantedID: 4, ItemType: ItemTypePart, ItemNumber: "3024", ColorID: 86, WantedQty: 100, WantedCondition: WantedConditionAny},
+		}},
+		{WantedListInfo: WantedListInfo{Name: "[LOOSE] Bin A", ID: 4}, WantedItems: []WantedItem{
+			{WantedID: 5, WantedListName: "[LOOSE] Bin A", ItemType: ItemTypePart, ItemNumber: "3024", ColorID: 86, ColorName: "Light Bluish Gray", WantedQty: 10, WantedCondition: WantedConditionUsed},
+			{WantedID: 6, WantedListName: "[LOOSE] Bin A", ItemType: ItemTypePart, ItemNumber: "3001", ColorID: 11, WantedQty: 4, WantedCondition: WantedConditionUsed},
+			{WantedID: 7, WantedListName: "[LOOSE] Bin A", ItemType: ItemTypePart, ItemNumber: "3024", ColorID: 11, WantedQty: 4, WantedCondition: WantedConditionUsed},
+		}},
+		{WantedListInfo: WantedListInfo{Name: "[LOOSE] Bin B", ID: 5}, WantedItems: []WantedItem{
+			{WantedID: 8, WantedListName: "[LOOSE] Bin B", ItemType: ItemTypePart, ItemNumber: "3001", ColorID: 11, WantedQty: 1, WantedCondition: WantedConditionAny},
+		}},
+		// The default list is wanted even when named like a source.
+		{WantedListInfo: WantedListInfo{Name: "[LOOSE] Default", ID: 0}, WantedItems: []WantedItem{
+			{WantedID: 9, WantedListName: "[LOOSE] Default", ItemType: ItemTypePart, ItemNumber: "3024", ColorID: 11, WantedQty: 1, WantedCondition: WantedConditionUsed},
+		}},
+	}
+	allocs := MatchWantedLists(lists, DefaultListConventions)
+	if len(allocs) != 4 {
+		t.Fatalf("expected 4 allocations, but got %+v", allocs)
+	}
+	if a := allocs[0]; a.WantedID != 2 || a.SourceID != 8 || a.Quantity != 1 {
+		t.Errorf("expected any-condition source to fill new want, but got %+v", a)
+	}
+	if a := allocs[1]; a.WantedID != 9 || a.SourceID != 7 || a.Quantity != 1 || a.WantedListName != "[LOOSE] Default" {
+		t.Errorf("expected default list to be wanted, but got %+v", a)
+	}
+	if a := allocs[2]; a.WantedID != 1 || a.SourceID != 5 || a.Quantity != 8 || a.SourceListName != "Bin A" {
+		t.Errorf("unexpected allocation %+v", a)
+	}
+	if a := allocs[3]; a.WantedID != 3 || a.SourceID != 5 || a.Quantity != 2 {
+		t.Errorf("unexpected allocation %+v", a)
+	}
+	allocs = allocs[2:]
+
+	var b bytes.Buffer
+	if err := WriteAllocationsText(&b, allocs); err != nil {
+		t.Fatal(err)
+	}
+	want := "P Light Bluish Gray 3024 \n - 8 from Bin A to Castle\n - 2 from Bin A to Ship\n"
+	if b.String() != want {
+		t.Errorf("unexpected text output %q", b.String())
+	}
+	b.Reset()
+	if err := WriteAllocationsCSV(&b, allocs); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(b.String()), "\n"); len(lines) != 3 || lines[1] != "P,3024,,86,Light Bluish Gray,X,1,0,Castle,5,0,Bin A,8" {
+		t.Errorf("unexpected CSV output %q", b.String())
+	}
+	b.Reset()
+	if err := WriteAllocationsJSON(&b, nil); err != nil || strings.TrimSpace(b.String()) != "[]" {
+		t.Errorf("unexpected JSON output %q, %v", b.String(), err)
+	}
+}
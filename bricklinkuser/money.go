@@ -0,0 +1,32 @@
+package bricklinkuser
+
+import "github.com/andrewarchi/brick-apis/money"
+
+// Prices parses the price of an item in the currency of the store and in
+// the display currency of the user, such as "NOK 1,900.00" and
+// "US $218.76".
+func (d *CartItemDetail) Prices() (native, display money.Money, err error) {
+	native, err = money.Parse(d.NativePrice)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	display, err = money.Parse(d.SalePrice)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	return native, display, nil
+}
+
+// ObserveRates records the exchange rate implied by the prices of an item.
+// Items on sale are skipped, since the native price is before the discount.
+func (d *CartItemDetail) ObserveRates(rates *money.ImpliedRates) error {
+	if d.SalePercent != 0 {
+		return nil
+	}
+	native, display, err := d.Prices()
+	if err != nil {
+		return err
+	}
+	rates.Observe(native, display)
+	return nil
+}
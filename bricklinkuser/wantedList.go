@@ -96,6 +96,8 @@ const (
 	ItemTypeBook        ItemType        = "B"
 	ItemTypeGear        ItemType        = "G"
 	ItemTypeCatalog     ItemType        = "C"
+	ItemTypeInstruction ItemType        = "I"
+	ItemTypeOriginalBox ItemType        = "O"
 	WantedConditionAny  WantedCondition = "X"
 	WantedConditionNew  WantedCondition = "N"
 	WantedConditionUsed WantedCondition = "U"
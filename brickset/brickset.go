@@ -2,72 +2,106 @@ package brickset
 
 import (
 	"encoding/xml"
-	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
 const (
-	url      = "https://brickset.com/api"
-	endpoint = url + "/v2.asmx"
+	apiURL   = "https://brickset.com/api"
+	endpoint = apiURL + "/v2.asmx"
 )
 
-func getFormEncodedEndpoint(methodName string) string {
-	return fmt.Sprintf("%s/%s", endpoint, methodName)
+// Client enables the ability to make requests to the Brickset API. It holds
+// the API key and the user hash, logging in lazily when the hash is first
+// needed and again when it expires.
+type Client struct {
+	*session
+	c        *http.Client
+	endpoint string
 }
 
-// Client enables the ability to make requests to the Brickset API
-type Client struct {
-	c *http.Client
+// NewClient creates a new Brickset client. username and password may be
+// empty for requests that do not access a user's collection.
+func NewClient(apiKey, username, password string) *Client {
+	c := &Client{c: &http.Client{}, endpoint: endpoint}
+	c.session = &session{apiKey: apiKey, username: username, password: password, loginFunc: c.login, checkFunc: c.CheckUserHash}
+	return c
 }
 
-// NewClient creates a new Brickset client
-func NewClient() *Client {
-	return &Client{&http.Client{}}
+// APIError is an error reported by the Brickset API, as opposed to a
+// network or decoding error.
+type APIError struct {
+	Method  string
+	Message string
 }
 
-func (c *Client) makeRequest(method string, body string, result interface{}) error {
-	req, _ := http.NewRequest("POST", getFormEncodedEndpoint(method), strings.NewReader(body))
+func (err *APIError) Error() string {
+	return err.Method + ": " + err.Message
+}
+
+func (c *Client) makeRequest(method string, values url.Values, result interface{}) error {
+	values.Set("apiKey", c.apiKey)
+	req, err := http.NewRequest("POST", c.endpoint+"/"+method, strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
 	resp, err := c.c.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return &APIError{method, "status " + resp.Status}
+	}
 
 	return xml.NewDecoder(resp.Body).Decode(result)
 }
 
-// Login is used to login to the Brickset API
-func (c Client) Login(apiKey, username, password string) (string, error) {
-	body := fmt.Sprintf(`apiKey=%s&username=%s&password=%s`, apiKey, username, password)
-	r := &loginResponse{}
-	if err := c.makeRequest("login", body, r); err != nil {
-		return "", err
-	}
-	return r.Response, nil
-}
-
 type loginResponse struct {
 	XMLName  xml.Name `xml:"https://brickset.com/api/ string"`
 	Response string   `xml:",chardata"`
 }
 
 // GetSet is used to get the details for a single set
-func (c *Client) GetSet(apiKey, userHash, setID string) (*GetSetsResponse, error) {
-	body := fmt.Sprintf("apiKey=%s&userHash=%s&SetID=%s", apiKey, userHash, setID)
+func (c *Client) GetSet(setID string) (*GetSetsResponse, error) {
 	r := &GetSetsResponse{}
-	if err := c.makeRequest("getSet", body, r); err != nil {
+	if err := c.userRequest("getSet", url.Values{"SetID": {setID}}, r); err != nil {
 		return nil, err
 	}
 	return r, nil
 }
 
-// GetSets is used to get a list of sets from the Brickset API
-func (c *Client) GetSets(apiKey, userHash, query, theme, subTheme, setNumber, year, owned, wanted, orderBy, pageSize, pageNumber, userName string) (*GetSetsResponse, error) {
-	body := fmt.Sprintf("apiKey=%s&userHash=%s&query=%s&theme=%s&subtheme=%s&setNumber=%s&year=%s&owned=%s&wanted=%s&orderBy=%s&pageSize=%s&pageNumber=%s&userName=%s", apiKey, userHash, query, theme, subTheme, setNumber, year, owned, wanted, orderBy, pageSize, pageNumber, userName)
+// QuerySets is used to get a list of sets from the Brickset API
+func (c *Client) QuerySets(q SetQuery) (*GetSetsResponse, error) {
+	r := &GetSetsResponse{}
+	if err := c.userRequest("getSets", q.Values(), r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetSets is used to get a list of sets from the Brickset API.
+// Parameters are sent to getSets unchanged.
+//
+// Deprecated: Use QuerySets.
+func (c *Client) GetSets(query, theme, subTheme, setNumber, year, owned, wanted, orderBy, pageSize, pageNumber, userName string) (*GetSetsResponse, error) {
+	values := url.Values{
+		"query":      {query},
+		"theme":      {theme},
+		"subtheme":   {subTheme},
+		"setNumber":  {setNumber},
+		"year":       {year},
+		"owned":      {owned},
+		"wanted":     {wanted},
+		"orderBy":    {orderBy},
+		"pageSize":   {pageSize},
+		"pageNumber": {pageNumber},
+		"userName":   {userName},
+	}
 	r := &GetSetsResponse{}
-	if err := c.makeRequest("getSets", body, r); err != nil {
+	if err := c.userRequest("getSets", values, r); err != nil {
 		return nil, err
 	}
 	return r, nil
@@ -79,57 +113,58 @@ type GetSetsResponse struct {
 	Sets    []GetSetResponseItem `xml:"sets"`
 }
 
-// GetSetResponseItem is a single set returned by getSets
+// GetSetResponseItem is a single set returned by getSets. Numbers and prices
+// that Brickset leaves empty are decoded as unknown and dates as zero.
 type GetSetResponseItem struct {
-	XMLName              xml.Name `xml:"sets"`
-	SetID                int      `xml:"setID"`
-	Number               string   `xml:"number"`
-	NumberVariant        int      `xml:"numberVariant"`
-	Name                 string   `xml:"name"`
-	Year                 string   `xml:"year"`
-	Theme                string   `xml:"theme"`
-	ThemeGroup           string   `xml:"themeGroup"`
-	Subtheme             string   `xml:"subtheme"`
-	Pieces               string   `xml:"pieces"`
-	Minifigs             string   `xml:"minifigs"`
-	Image                bool     `xml:"image"`
-	ImageFilename        string   `xml:"imageFilename"`
-	ThumbnailURL         string   `xml:"thumbnailURL"`
-	LargeThumbnailURL    string   `xml:"largeThumbnailURL"`
-	ImageURL             string   `xml:"imageURL"`
-	BricksetURL          string   `xml:"bricksetURL"`
-	Released             bool     `xml:"released"`
-	Owned                bool     `xml:"owned"`
-	Wanted               bool     `xml:"wanted"`
-	QtyOwned             int      `xml:"qtyOwned"`
-	UserNotes            string   `xml:"userNotes"`
-	ACMDataCount         int      `xml:"ACMDataCount"`
-	OwnedByTotal         int      `xml:"ownedByTotal"`
-	WantedByTotal        int      `xml:"wantedByTotal"`
-	UKRetailPrice        string   `xml:"UKRetailPrice"`
-	USRetailPrice        string   `xml:"USRetailPrice"`
-	CARetailPrice        string   `xml:"CARetailPrice"`
-	EURetailPrice        string   `xml:"EURetailPrice"`
-	USDateAddedToSAH     string   `xml:"USDateAddedToSAH"`
-	USDateRemovedFromSAH string   `xml:"USDateRemovedFromSAH"`
-	Rating               float32  `xml:"rating"`
-	ReviewCount          int      `xml:"reviewCount"`
-	PackagingType        string   `xml:"packagingType"`
-	Availability         string   `xml:"availability"`
-	InstructionsCount    int      `xml:"instructionsCount"`
-	AdditionalImageCount int      `xml:"additionalImageCount"`
-	AgeMin               string   `xml:"ageMin"`
-	AgeMax               string   `xml:"ageMax"`
-	Height               string   `xml:"height"`
-	Width                string   `xml:"width"`
-	Depth                string   `xml:"depth"`
-	Weight               string   `xml:"weight"`
-	Category             string   `xml:"category"`
-	Notes                string   `xml:"notes"`
-	UserRating           string   `xml:"userRating"`
-	Tags                 string   `xml:"tags"`
-	EAN                  string   `xml:"EAN"`
-	UPC                  string   `xml:"UPC"`
-	Description          string   `xml:"description"`
-	LastUpdated          string   `xml:"lastUpdated"`
+	XMLName              xml.Name      `xml:"sets"`
+	SetID                int           `xml:"setID"`
+	Number               string        `xml:"number"`
+	NumberVariant        int           `xml:"numberVariant"`
+	Name                 string        `xml:"name"`
+	Year                 OptionalInt   `xml:"year"`
+	Theme                string        `xml:"theme"`
+	ThemeGroup           string        `xml:"themeGroup"`
+	Subtheme             string        `xml:"subtheme"`
+	Pieces               OptionalInt   `xml:"pieces"`
+	Minifigs             OptionalInt   `xml:"minifigs"`
+	Image                bool          `xml:"image"`
+	ImageFilename        string        `xml:"imageFilename"`
+	ThumbnailURL         string        `xml:"thumbnailURL"`
+	LargeThumbnailURL    string        `xml:"largeThumbnailURL"`
+	ImageURL             string        `xml:"imageURL"`
+	BricksetURL          string        `xml:"bricksetURL"`
+	Released             bool          `xml:"released"`
+	Owned                bool          `xml:"owned"`
+	Wanted               bool          `xml:"wanted"`
+	QtyOwned             int           `xml:"qtyOwned"`
+	UserNotes            string        `xml:"userNotes"`
+	ACMDataCount         int           `xml:"ACMDataCount"`
+	OwnedByTotal         int           `xml:"ownedByTotal"`
+	WantedByTotal        int           `xml:"wantedByTotal"`
+	UKRetailPrice        Price         `xml:"UKRetailPrice"`
+	USRetailPrice        Price         `xml:"USRetailPrice"`
+	CARetailPrice        Price         `xml:"CARetailPrice"`
+	EURetailPrice        Price         `xml:"EURetailPrice"`
+	USDateAddedToSAH     Time          `xml:"USDateAddedToSAH"`
+	USDateRemovedFromSAH Time          `xml:"USDateRemovedFromSAH"`
+	Rating               float32       `xml:"rating"`
+	ReviewCount          int           `xml:"reviewCount"`
+	PackagingType        string        `xml:"packagingType"`
+	Availability         string        `xml:"availability"`
+	InstructionsCount    int           `xml:"instructionsCount"`
+	AdditionalImageCount int           `xml:"additionalImageCount"`
+	AgeMin               OptionalInt   `xml:"ageMin"`
+	AgeMax               OptionalInt   `xml:"ageMax"`
+	Height               OptionalFloat `xml:"height"`
+	Width                OptionalFloat `xml:"width"`
+	Depth                OptionalFloat `xml:"depth"`
+	Weight               OptionalFloat `xml:"weight"`
+	Category             string        `xml:"category"`
+	Notes                string        `xml:"notes"`
+	UserRating           string        `xml:"userRating"`
+	Tags                 string        `xml:"tags"`
+	EAN                  string        `xml:"EAN"`
+	UPC                  string        `xml:"UPC"`
+	Description          string        `xml:"description"`
+	LastUpdated          Time          `xml:"lastUpdated"`
 }
@@ -5,6 +5,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/money"
 )
 
 var (
@@ -18,8 +21,8 @@ func TestLogin(t *testing.T) {
 	if testing.Short() {
 		t.SkipNow()
 	}
-	c := NewClient()
-	userHash, err := c.Login(apiKey, username, password)
+	c := NewClient(apiKey, username, password)
+	userHash, err := c.Login()
 	if err != nil {
 		t.Error(err)
 	}
@@ -30,8 +33,9 @@ func TestGetSet(t *testing.T) {
 	if testing.Short() {
 		t.SkipNow()
 	}
-	c := NewClient()
-	res, err := c.GetSet(apiKey, userHash, "22667")
+	c := NewClient(apiKey, username, password)
+	c.SetUserHash(userHash)
+	res, err := c.GetSet("22667")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -42,8 +46,9 @@ func TestGetSets(t *testing.T) {
 	if testing.Short() {
 		t.SkipNow()
 	}
-	c := NewClient()
-	res, err := c.GetSets(apiKey, userHash, "", "", "", "", "", "", "", "", "", "", "")
+	c := NewClient(apiKey, username, password)
+	c.SetUserHash(userHash)
+	res, err := c.GetSets("", "", "", "", "", "", "", "", "", "", "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -139,6 +144,25 @@ func TestDecodeGetSetsXml(t *testing.T) {
 		t.Fatal(err)
 	}
 	if len(r.Sets) != 1 {
-		t.Error("Expected correct string value", len(r.Sets))
+		t.Fatal("Expected correct string value", len(r.Sets))
+	}
+	s := r.Sets[0]
+	if s.Year != (OptionalInt{1965, true}) || s.Pieces != (OptionalInt{43, true}) || s.Minifigs.Known {
+		t.Errorf("unexpected counts: year %v, pieces %v, minifigs %v", s.Year, s.Pieces, s.Minifigs)
+	}
+	if s.USRetailPrice != (Price{money.MustParseDecimal("4.95"), "USD", true}) || s.UKRetailPrice != (Price{0, "GBP", false}) {
+		t.Errorf("unexpected prices: %v, %v", s.USRetailPrice, s.UKRetailPrice)
+	}
+	if s.AgeMin.Value != 5 || s.AgeMax.Value != 12 || s.Height.Value != 20.3 || s.Weight.Known {
+		t.Errorf("unexpected ages or dimensions: %v-%v, %v, %v", s.AgeMin, s.AgeMax, s.Height, s.Weight)
+	}
+	if want := time.Date(2018, 1, 29, 10, 24, 39, 983000000, time.UTC); !s.LastUpdated.Equal(want) || !s.USDateAddedToSAH.IsZero() {
+		t.Errorf("unexpected dates: %v, %v", s.LastUpdated, s.USDateAddedToSAH)
+	}
+	if ppp, ok := s.PricePerPiece(CurrencyUS); !ok || ppp != 4.95/43 {
+		t.Errorf("unexpected price per piece %v, %v", ppp, ok)
+	}
+	if _, ok := s.PricePerPiece(CurrencyEU); ok {
+		t.Error("expected unknown EUR price per piece")
 	}
 }
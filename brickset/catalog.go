@@ -0,0 +1,175 @@
+package brickset
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// GetThemes returns all themes with the number of sets and years they span.
+func (c *ClientV3) GetThemes() ([]Theme, error) {
+	var r themesResponse
+	if err := c.makeRequest("getThemes", url.Values{}, &r); err != nil {
+		return nil, err
+	}
+	return r.Themes, nil
+}
+
+type themesResponse struct {
+	v3Response
+	Matches int     `json:"matches"`
+	Themes  []Theme `json:"themes"`
+}
+
+// Theme summarizes the sets in a theme.
+type Theme struct {
+	Theme         string `json:"theme"`
+	SetCount      int    `json:"setCount"`
+	SubthemeCount int    `json:"subthemeCount"`
+	YearFrom      int    `json:"yearFrom"`
+	YearTo        int    `json:"yearTo"`
+}
+
+// GetSubthemes returns the subthemes of a theme.
+func (c *ClientV3) GetSubthemes(theme string) ([]Subtheme, error) {
+	var r subthemesResponse
+	if err := c.makeRequest("getSubthemes", url.Values{"Theme": {theme}}, &r); err != nil {
+		return nil, err
+	}
+	return r.Subthemes, nil
+}
+
+type subthemesResponse struct {
+	v3Response
+	Matches   int        `json:"matches"`
+	Subthemes []Subtheme `json:"subthemes"`
+}
+
+// Subtheme summarizes the sets in a subtheme.
+type Subtheme struct {
+	Theme    string `json:"theme"`
+	Subtheme string `json:"subtheme"`
+	SetCount int    `json:"setCount"`
+	YearFrom int    `json:"yearFrom"`
+	YearTo   int    `json:"yearTo"`
+}
+
+// GetYears returns the years in which sets were released in a theme, or in
+// all themes when theme is empty.
+func (c *ClientV3) GetYears(theme string) ([]Year, error) {
+	var r yearsResponse
+	if err := c.makeRequest("getYears", url.Values{"Theme": {theme}}, &r); err != nil {
+		return nil, err
+	}
+	return r.Years, nil
+}
+
+type yearsResponse struct {
+	v3Response
+	Matches int    `json:"matches"`
+	Years   []Year `json:"years"`
+}
+
+// Year summarizes the sets released in a year.
+type Year struct {
+	Theme    string `json:"theme"`
+	Year     int    `json:"year"`
+	SetCount int    `json:"setCount"`
+}
+
+// UnmarshalJSON decodes a year, which Brickset sends as a string.
+func (y *Year) UnmarshalJSON(data []byte) error {
+	var r struct {
+		Theme    string          `json:"theme"`
+		Year     json.RawMessage `json:"year"`
+		SetCount int             `json:"setCount"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	year := string(r.Year)
+	if s, err := strconv.Unquote(year); err == nil {
+		year = s
+	}
+	n, err := strconv.Atoi(year)
+	if err != nil {
+		return fmt.Errorf("invalid year %s", r.Year)
+	}
+	*y = Year{Theme: r.Theme, Year: n, SetCount: r.SetCount}
+	return nil
+}
+
+// GetInstructions returns the building instructions available for a set.
+func (c *ClientV3) GetInstructions(setID int) ([]Instructions, error) {
+	var r instructionsResponse
+	if err := c.makeRequest("getInstructions", setValues(setID), &r); err != nil {
+		return nil, err
+	}
+	return r.Instructions, nil
+}
+
+type instructionsResponse struct {
+	v3Response
+	Matches      int            `json:"matches"`
+	Instructions []Instructions `json:"instructions"`
+}
+
+// Instructions is a link to a PDF of building instructions.
+type Instructions struct {
+	URL         string `json:"URL"`
+	Description string `json:"description"`
+}
+
+// GetAdditionalImages returns the images of a set other than the main image.
+func (c *ClientV3) GetAdditionalImages(setID int) ([]SetImage, error) {
+	var r additionalImagesResponse
+	if err := c.makeRequest("getAdditionalImages", setValues(setID), &r); err != nil {
+		return nil, err
+	}
+	return r.AdditionalImages, nil
+}
+
+type additionalImagesResponse struct {
+	v3Response
+	Matches          int        `json:"matches"`
+	AdditionalImages []SetImage `json:"additionalImages"`
+}
+
+// GetReviews returns the user reviews of a set.
+func (c *ClientV3) GetReviews(setID int) ([]Review, error) {
+	var r reviewsResponse
+	if err := c.makeRequest("getReviews", setValues(setID), &r); err != nil {
+		return nil, err
+	}
+	return r.Reviews, nil
+}
+
+type reviewsResponse struct {
+	v3Response
+	Matches int      `json:"matches"`
+	Reviews []Review `json:"reviews"`
+}
+
+// Review is a user review of a set.
+type Review struct {
+	Author     string       `json:"author"`
+	DatePosted Time         `json:"datePosted"`
+	Rating     ReviewRating `json:"rating"`
+	Title      string       `json:"title"`
+	Review     string       `json:"review"`
+	HTML       bool         `json:"HTML"` // Whether Review is formatted as HTML
+}
+
+// ReviewRating contains the ratings of a review out of 5.
+type ReviewRating struct {
+	Overall            int `json:"overall"`
+	Parts              int `json:"parts"`
+	BuildingExperience int `json:"buildingExperience"`
+	Playability        int `json:"playability"`
+	ValueForMoney      int `json:"valueForMoney"`
+}
+
+func setValues(setID int) url.Values {
+	return url.Values{"setID": {strconv.Itoa(setID)}}
+}
@@ -0,0 +1,38 @@
+package brickset
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetYears(t *testing.T) {
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/getYears" || r.FormValue("Theme") != "Star Wars" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Form)
+		}
+		w.Write([]byte(`{"status":"success","matches":2,"years":[{"theme":"Star Wars","year":"1999","setCount":13},{"theme":"Star Wars","year":2000,"setCount":24}]}`))
+	})
+	years, err := c.GetYears("Star Wars")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(years) != 2 || years[0].Year != 1999 || years[1].Year != 2000 || years[1].SetCount != 24 {
+		t.Errorf("unexpected years %+v", years)
+	}
+}
+
+func TestGetReviews(t *testing.T) {
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/getReviews" || r.FormValue("setID") != "26725" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Form)
+		}
+		w.Write([]byte(`{"status":"success","matches":1,"reviews":[{"author":"Huw","datePosted":"2017-10-05T12:00:00","rating":{"overall":5,"parts":5,"buildingExperience":4,"playability":3,"valueForMoney":4},"title":"Worth it","review":"<p>Big.</p>","HTML":true}]}`))
+	})
+	reviews, err := c.GetReviews(26725)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviews) != 1 || reviews[0].Rating.Overall != 5 || !reviews[0].HTML || reviews[0].DatePosted.Year() != 2017 {
+		t.Errorf("unexpected reviews %+v", reviews)
+	}
+}
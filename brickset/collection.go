@@ -0,0 +1,122 @@
+package brickset
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CollectionUpdate contains the collection details to change for a set or
+// minifig. Nil fields are left unchanged.
+type CollectionUpdate struct {
+	Own      *bool
+	Want     *bool
+	QtyOwned *int    // For minifigs, the number owned loose
+	Notes    *string // Sets only
+	Rating   *int    // Sets only, from 1 to 5, or 0 to clear it
+}
+
+func (u CollectionUpdate) params() collectionParams {
+	params := collectionParams{QtyOwned: u.QtyOwned, Notes: u.Notes, Rating: u.Rating}
+	if u.Own != nil {
+		params.Own = boolInt(*u.Own)
+	}
+	if u.Want != nil {
+		params.Want = boolInt(*u.Want)
+	}
+	return params
+}
+
+// SetCollection updates the user's collection details for a set, sending
+// only the fields that are set, so that marking a set owned keeps its
+// notes and rating.
+func (c *ClientV3) SetCollection(setID int, update CollectionUpdate) error {
+	if update.Rating != nil && (*update.Rating < 0 || *update.Rating > 5) {
+		return fmt.Errorf("setCollection: rating %d out of range", *update.Rating)
+	}
+	values, err := paramsValues(update.params())
+	if err != nil {
+		return err
+	}
+	values.Set("setID", strconv.Itoa(setID))
+	var r v3Response
+	return c.userRequest("setCollection", values, &r)
+}
+
+// collectionParams are the params of setCollection and setMinifigCollection.
+// Nil fields are left unchanged.
+type collectionParams struct {
+	Own      *int    `json:"own,omitempty"`  // 1 or 0
+	Want     *int    `json:"want,omitempty"` // 1 or 0
+	QtyOwned *int    `json:"qtyOwned,omitempty"`
+	Notes    *string `json:"notes,omitempty"`
+	Rating   *int    `json:"rating,omitempty"`
+}
+
+func boolInt(b bool) *int {
+	i := 0
+	if b {
+		i = 1
+	}
+	return &i
+}
+
+// GetMinifigCollection returns the minifigs in the user's collection. When
+// owned or wanted is set, only minifigs owned or wanted are returned. Query
+// filters by minifig number or name.
+func (c *ClientV3) GetMinifigCollection(owned, wanted bool, query string) ([]Minifig, error) {
+	params := minifigCollectionParams{Query: query}
+	if owned {
+		params.Owned = boolInt(owned)
+	}
+	if wanted {
+		params.Wanted = boolInt(wanted)
+	}
+	values, err := paramsValues(params)
+	if err != nil {
+		return nil, err
+	}
+	var r minifigCollectionResponse
+	if err := c.userRequest("getMinifigCollection", values, &r); err != nil {
+		return nil, err
+	}
+	return r.Minifigs, nil
+}
+
+type minifigCollectionParams struct {
+	Owned  *int   `json:"owned,omitempty"`
+	Wanted *int   `json:"wanted,omitempty"`
+	Query  string `json:"query,omitempty"`
+}
+
+type minifigCollectionResponse struct {
+	v3Response
+	Matches  int       `json:"matches"`
+	Minifigs []Minifig `json:"minifigs"`
+}
+
+// Minifig is a minifig in the user's collection.
+type Minifig struct {
+	MinifigNumber string `json:"minifigNumber"` // BrickLink minifig number, e.g. "sw0001a"
+	Name          string `json:"name"`
+	Category      string `json:"category"`
+	OwnedInSets   int    `json:"ownedInSets"`
+	OwnedLoose    int    `json:"ownedLoose"`
+	OwnedTotal    int    `json:"ownedTotal"`
+	Wanted        bool   `json:"wanted"`
+}
+
+// SetMinifigCollection updates the user's collection details for a
+// minifig, sending only the fields that are set. Minifigs have no notes or
+// rating.
+func (c *ClientV3) SetMinifigCollection(minifigNumber string, update CollectionUpdate) error {
+	if update.Notes != nil || update.Rating != nil {
+		return fmt.Errorf("setMinifigCollection: minifigs have no notes or rating")
+	}
+	values, err := paramsValues(update.params())
+	if err != nil {
+		return err
+	}
+	values.Set("minifigNumber", minifigNumber)
+	var r v3Response
+	return c.userRequest("setMinifigCollection", values, &r)
+}
@@ -0,0 +1,49 @@
+package brickset
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSetCollection(t *testing.T) {
+	var want string
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/setCollection" || r.FormValue("setID") != "26725" || r.FormValue("userHash") != "hash" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Form)
+		}
+		if params := r.FormValue("params"); params != want {
+			t.Errorf("expected params %s, but got %s", want, params)
+		}
+		w.Write([]byte(`{"status":"success"}`))
+	})
+	own, wanted, qty, notes, rating := true, false, 2, "Bought from BrickLink order #1234", 5
+	want = `{"own":1,"want":0,"qtyOwned":2,"notes":"Bought from BrickLink order #1234","rating":5}`
+	if err := c.SetCollection(26725, CollectionUpdate{&own, &wanted, &qty, &notes, &rating}); err != nil {
+		t.Fatal(err)
+	}
+	// Marking a set owned leaves the other details unchanged.
+	want = `{"own":1}`
+	if err := c.SetCollection(26725, CollectionUpdate{Own: &own}); err != nil {
+		t.Fatal(err)
+	}
+	rating = 6
+	if err := c.SetCollection(26725, CollectionUpdate{Rating: &rating}); err == nil {
+		t.Error("expected error for out of range rating")
+	}
+}
+
+func TestGetMinifigCollection(t *testing.T) {
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		if params := r.FormValue("params"); params != `{"owned":1}` {
+			t.Errorf("unexpected params %s", params)
+		}
+		w.Write([]byte(`{"status":"success","matches":1,"minifigs":[{"minifigNumber":"sw0001a","name":"Battle Droid","category":"Star Wars","ownedInSets":2,"ownedLoose":1,"ownedTotal":3,"wanted":false}]}`))
+	})
+	minifigs, err := c.GetMinifigCollection(true, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(minifigs) != 1 || minifigs[0].MinifigNumber != "sw0001a" || minifigs[0].OwnedTotal != 3 {
+		t.Errorf("unexpected minifigs %+v", minifigs)
+	}
+}
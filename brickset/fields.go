@@ -0,0 +1,274 @@
+package brickset
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+// OptionalInt is an integer that Brickset may leave empty when unknown.
+type OptionalInt struct {
+	Value int
+	Known bool
+}
+
+// UnmarshalText decodes an integer, treating empty text as unknown.
+func (n *OptionalInt) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*n = OptionalInt{}
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid integer %q", s)
+	}
+	*n = OptionalInt{v, true}
+	return nil
+}
+
+// MarshalText encodes the integer, or empty text when unknown.
+func (n OptionalInt) MarshalText() ([]byte, error) {
+	if !n.Known {
+		return []byte{}, nil
+	}
+	return []byte(strconv.Itoa(n.Value)), nil
+}
+
+// UnmarshalJSON decodes a number or numeric string, treating null as
+// unknown.
+func (n *OptionalInt) UnmarshalJSON(data []byte) error {
+	return n.UnmarshalText(jsonText(data))
+}
+
+// MarshalJSON encodes the integer, or null when unknown.
+func (n OptionalInt) MarshalJSON() ([]byte, error) {
+	if !n.Known {
+		return []byte("null"), nil
+	}
+	return n.MarshalText()
+}
+
+func (n OptionalInt) String() string {
+	if !n.Known {
+		return "unknown"
+	}
+	return strconv.Itoa(n.Value)
+}
+
+// OptionalFloat is a decimal number that Brickset may leave empty when
+// unknown.
+type OptionalFloat struct {
+	Value float64
+	Known bool
+}
+
+// UnmarshalText decodes a decimal number, treating empty text as unknown.
+func (f *OptionalFloat) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		*f = OptionalFloat{}
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %q", s)
+	}
+	*f = OptionalFloat{v, true}
+	return nil
+}
+
+// MarshalText encodes the number, or empty text when unknown.
+func (f OptionalFloat) MarshalText() ([]byte, error) {
+	if !f.Known {
+		return []byte{}, nil
+	}
+	return []byte(strconv.FormatFloat(f.Value, 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON decodes a number or numeric string, treating null as
+// unknown.
+func (f *OptionalFloat) UnmarshalJSON(data []byte) error {
+	return f.UnmarshalText(jsonText(data))
+}
+
+// MarshalJSON encodes the number, or null when unknown.
+func (f OptionalFloat) MarshalJSON() ([]byte, error) {
+	if !f.Known {
+		return []byte("null"), nil
+	}
+	return f.MarshalText()
+}
+
+// jsonText returns the text of a JSON number or string, or empty text for
+// null.
+func jsonText(data []byte) []byte {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if u, err := strconv.Unquote(s); err == nil {
+		return []byte(u)
+	}
+	return data
+}
+
+func (f OptionalFloat) String() string {
+	if !f.Known {
+		return "unknown"
+	}
+	return strconv.FormatFloat(f.Value, 'f', -1, 64)
+}
+
+// Price is a retail price in a currency. Known is false when Brickset has
+// no price for the region.
+type Price struct {
+	Amount   money.Decimal
+	Currency money.Currency
+	Known    bool
+}
+
+// UnmarshalText decodes the amount of a price, treating empty text as
+// unknown. The currency is set by the containing set.
+func (p *Price) UnmarshalText(text []byte) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" {
+		p.Amount, p.Known = 0, false
+		return nil
+	}
+	amount, err := money.ParseDecimal(s)
+	if err != nil {
+		return fmt.Errorf("invalid price %q", s)
+	}
+	p.Amount, p.Known = amount, true
+	return nil
+}
+
+// MarshalText encodes the amount of the price, or empty text when unknown.
+func (p Price) MarshalText() ([]byte, error) {
+	if !p.Known {
+		return []byte{}, nil
+	}
+	return p.Amount.MarshalText()
+}
+
+// UnmarshalJSON decodes the amount of a price from a number or numeric
+// string, treating null as unknown.
+func (p *Price) UnmarshalJSON(data []byte) error {
+	return p.UnmarshalText(jsonText(data))
+}
+
+// MarshalJSON encodes the amount of the price, or null when unknown.
+func (p Price) MarshalJSON() ([]byte, error) {
+	if !p.Known {
+		return []byte("null"), nil
+	}
+	return p.MarshalText()
+}
+
+// Money returns the price as an amount of money. It is only meaningful
+// when the price is known.
+func (p Price) Money() money.Money {
+	return money.New(p.Amount, p.Currency)
+}
+
+func (p Price) String() string {
+	if !p.Known {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s %s", p.Currency, p.Amount.StringFixed(2))
+}
+
+// UnmarshalText decodes a timestamp with or without a time zone, treating
+// empty text as the zero time.
+func (t *Time) UnmarshalText(text []byte) error {
+	var err error
+	*t, err = parseTime(strings.TrimSpace(string(text)))
+	return err
+}
+
+// MarshalText encodes the timestamp, or empty text for the zero time.
+func (t Time) MarshalText() ([]byte, error) {
+	if t.IsZero() {
+		return []byte{}, nil
+	}
+	return t.Time.MarshalText()
+}
+
+// Currencies of the regional retail prices of a set.
+const (
+	CurrencyUK money.Currency = "GBP"
+	CurrencyUS money.Currency = "USD"
+	CurrencyCA money.Currency = "CAD"
+	CurrencyEU money.Currency = "EUR"
+)
+
+// UnmarshalXML decodes a set and sets the currencies of its retail prices.
+func (s *GetSetResponseItem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type item GetSetResponseItem
+	if err := d.DecodeElement((*item)(s), &start); err != nil {
+		return err
+	}
+	s.UKRetailPrice.Currency = CurrencyUK
+	s.USRetailPrice.Currency = CurrencyUS
+	s.CARetailPrice.Currency = CurrencyCA
+	s.EURetailPrice.Currency = CurrencyEU
+	return nil
+}
+
+// RetailPrice returns the retail price of the set in a currency.
+func (s *GetSetResponseItem) RetailPrice(currency money.Currency) Price {
+	return findPrice(currency, s.UKRetailPrice, s.USRetailPrice, s.CARetailPrice, s.EURetailPrice)
+}
+
+// PricePerPiece returns the retail price in a currency divided by the
+// number of pieces. It reports false when either is unknown.
+func (s *GetSetResponseItem) PricePerPiece(currency money.Currency) (float64, bool) {
+	return pricePerPiece(s.RetailPrice(currency), s.Pieces)
+}
+
+// UnmarshalJSON decodes the regional details and sets the currencies of
+// their retail prices.
+func (l *LEGOCom) UnmarshalJSON(data []byte) error {
+	type legoCom LEGOCom
+	if err := json.Unmarshal(data, (*legoCom)(l)); err != nil {
+		return err
+	}
+	l.US.RetailPrice.Currency = CurrencyUS
+	l.UK.RetailPrice.Currency = CurrencyUK
+	l.CA.RetailPrice.Currency = CurrencyCA
+	l.DE.RetailPrice.Currency = CurrencyEU
+	return nil
+}
+
+// RetailPrice returns the LEGO.com retail price of the set in a currency.
+func (s *Set) RetailPrice(currency money.Currency) Price {
+	l := &s.LEGOCom
+	return findPrice(currency, l.UK.RetailPrice, l.US.RetailPrice, l.CA.RetailPrice, l.DE.RetailPrice)
+}
+
+// PricePerPiece returns the retail price in a currency divided by the
+// number of pieces. It reports false when either is unknown.
+func (s *Set) PricePerPiece(currency money.Currency) (float64, bool) {
+	return pricePerPiece(s.RetailPrice(currency), s.Pieces)
+}
+
+func findPrice(currency money.Currency, prices ...Price) Price {
+	for _, p := range prices {
+		if p.Currency == currency {
+			return p
+		}
+	}
+	return Price{Currency: currency}
+}
+
+func pricePerPiece(p Price, pieces OptionalInt) (float64, bool) {
+	if !p.Known || !pieces.Known || pieces.Value <= 0 {
+		return 0, false
+	}
+	return p.Amount.Float64() / float64(pieces.Value), true
+}
@@ -0,0 +1,100 @@
+package brickset
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SetQuery contains the search parameters of getSets. Lists match any of
+// their values and empty fields are not filtered on.
+type SetQuery struct {
+	Query      string      // Search term matched against set number, name, theme and subtheme
+	Themes     []string    // Theme names
+	Subthemes  []string    // Subtheme names
+	SetNumbers []string    // Set numbers in the form "75192-1"
+	Years      []YearRange // Release years
+	Owned      bool        // Only sets owned by the user
+	Wanted     bool        // Only sets wanted by the user
+	OrderBy    OrderBy     // Sort order, defaulting to OrderByNumber
+	PageSize   int         // Sets per page, defaulting to 20
+	PageNumber int         // 1-based page number, defaulting to 1
+	UserName   string      // Return sets owned or wanted by another user, given Owned or Wanted
+}
+
+// YearRange is an inclusive range of release years.
+type YearRange struct {
+	From, To int
+}
+
+// Years returns a range of release years from from to to, inclusive.
+func Years(from, to int) YearRange {
+	return YearRange{from, to}
+}
+
+// OrderBy is a getSets sort order. Orders are ascending unless made
+// descending with Descending.
+type OrderBy string
+
+// Available values for OrderBy.
+const (
+	OrderByNumber        OrderBy = "Number"
+	OrderByYearFrom      OrderBy = "YearFrom"
+	OrderByPieces        OrderBy = "Pieces"
+	OrderByMinifigs      OrderBy = "Minifigs"
+	OrderByRating        OrderBy = "Rating"
+	OrderByUKRetailPrice OrderBy = "UKRetailPrice"
+	OrderByUSRetailPrice OrderBy = "USRetailPrice"
+	OrderByCARetailPrice OrderBy = "CARetailPrice"
+	OrderByEURetailPrice OrderBy = "EURetailPrice"
+	OrderByTheme         OrderBy = "Theme"
+	OrderBySubtheme      OrderBy = "Subtheme"
+	OrderByName          OrderBy = "Name"
+	OrderByRandom        OrderBy = "Random"
+)
+
+// Descending returns the descending form of the order.
+func (o OrderBy) Descending() OrderBy {
+	if strings.HasSuffix(string(o), "DESC") || o == OrderByRandom {
+		return o
+	}
+	return o + "DESC"
+}
+
+// Values encodes the query as getSets form values. Every parameter is
+// present, as the v2 API rejects requests with missing parameters.
+func (q SetQuery) Values() url.Values {
+	var years []string
+	for _, r := range q.Years {
+		for y := r.From; y <= r.To; y++ {
+			years = append(years, strconv.Itoa(y))
+		}
+	}
+	return url.Values{
+		"query":      {q.Query},
+		"theme":      {strings.Join(q.Themes, ",")},
+		"subtheme":   {strings.Join(q.Subthemes, ",")},
+		"setNumber":  {strings.Join(q.SetNumbers, ",")},
+		"year":       {strings.Join(years, ",")},
+		"owned":      {flag(q.Owned)},
+		"wanted":     {flag(q.Wanted)},
+		"orderBy":    {string(q.OrderBy)},
+		"pageSize":   {intOrEmpty(q.PageSize)},
+		"pageNumber": {intOrEmpty(q.PageNumber)},
+		"userName":   {q.UserName},
+	}
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return ""
+}
+
+func intOrEmpty(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,78 @@
+package brickset
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSetQueryValues(t *testing.T) {
+	q := SetQuery{
+		Query:    "Millennium Falcon & X-Wing",
+		Themes:   []string{"Star Wars", "Ideas"},
+		Years:    []YearRange{Years(2017, 2019), {2021, 2021}},
+		Owned:    true,
+		OrderBy:  OrderByPieces.Descending(),
+		PageSize: 50,
+	}
+	values := q.Values()
+	want := map[string]string{
+		"query":      "Millennium Falcon & X-Wing",
+		"theme":      "Star Wars,Ideas",
+		"subtheme":   "",
+		"setNumber":  "",
+		"year":       "2017,2018,2019,2021",
+		"owned":      "1",
+		"wanted":     "",
+		"orderBy":    "PiecesDESC",
+		"pageSize":   "50",
+		"pageNumber": "",
+		"userName":   "",
+	}
+	for key, value := range want {
+		if v, ok := values[key]; !ok || len(v) != 1 || v[0] != value {
+			t.Errorf("%s: got %q, want %q", key, v, value)
+		}
+	}
+	decoded, err := url.ParseQuery(values.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Get("query"); got != q.Query {
+		t.Errorf("query did not round trip: got %q", got)
+	}
+}
+
+func TestOrderByDescending(t *testing.T) {
+	tests := []struct {
+		order, want OrderBy
+	}{
+		{OrderByNumber, "NumberDESC"},
+		{OrderByNumber.Descending(), "NumberDESC"},
+		{OrderByRandom, "Random"},
+	}
+	for _, test := range tests {
+		if got := test.order.Descending(); got != test.want {
+			t.Errorf("%s.Descending() = %s, want %s", test.order, got, test.want)
+		}
+	}
+}
+
+func TestGetSetsPassesStrings(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			writeString(w, "hash")
+		case "/getSets":
+			for key, want := range map[string]string{"year": "2019, 2020s", "owned": "yes", "pageSize": "x", "query": "a & b"} {
+				if got := r.FormValue(key); got != want {
+					t.Errorf("%s: got %q, want %q", key, got, want)
+				}
+			}
+			w.Write([]byte(`<ArrayOfSets xmlns="https://brickset.com/api/"></ArrayOfSets>`))
+		}
+	})
+	if _, err := c.GetSets("a & b", "", "", "", "2019, 2020s", "yes", "", "", "x", "", ""); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,171 @@
+package brickset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hashCheckInterval is how long a user hash is trusted before it is
+// validated again with checkUserHash.
+const hashCheckInterval = time.Hour
+
+// session holds the API key and user credentials of a client and the user
+// hash obtained with them. It logs in lazily when the hash is first needed
+// and again when it expires. Client and ClientV3 embed it, supplying the
+// login and checkUserHash requests of their API version.
+type session struct {
+	apiKey   string
+	username string
+	password string
+
+	loginFunc func(username, password string) (string, error)
+	checkFunc func(userHash string) (bool, error)
+
+	mu       sync.Mutex
+	userHash string
+	checked  time.Time // When userHash was last known to be valid
+	hashFile string
+}
+
+// Login logs in with the client's username and password and replaces the
+// user hash, which is returned.
+func (s *session) Login() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renew()
+}
+
+// UserHash returns a valid user hash, logging in when there is none or the
+// current one has expired. It is empty when the client has no username.
+func (s *session) UserHash() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentHash()
+}
+
+// SetUserHash sets a user hash obtained elsewhere. It is validated before
+// first use.
+func (s *session) SetUserHash(userHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.userHash = userHash
+	s.checked = time.Time{}
+}
+
+// UseHashFile persists the user hash in a file. A hash already saved in
+// the file is loaded and validated before first use and each new hash is
+// written to it.
+func (s *session) UseHashFile(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hashFile = path
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.userHash = strings.TrimSpace(string(data))
+	s.checked = time.Time{}
+	return nil
+}
+
+func (s *session) currentHash() (string, error) {
+	if s.userHash != "" {
+		if time.Since(s.checked) < hashCheckInterval {
+			return s.userHash, nil
+		}
+		valid, err := s.checkFunc(s.userHash)
+		if err != nil {
+			return "", err
+		}
+		if valid {
+			s.checked = time.Now()
+			return s.userHash, nil
+		}
+		s.userHash = ""
+	}
+	if s.username == "" {
+		return "", nil
+	}
+	return s.renew()
+}
+
+func (s *session) renew() (string, error) {
+	if s.username == "" {
+		return "", fmt.Errorf("login: no username")
+	}
+	hash, err := s.loginFunc(s.username, s.password)
+	if err != nil {
+		return "", err
+	}
+	s.userHash = hash
+	s.checked = time.Now()
+	if s.hashFile != "" {
+		if err := ioutil.WriteFile(s.hashFile, []byte(hash+"\n"), 0600); err != nil {
+			return "", err
+		}
+	}
+	return hash, nil
+}
+
+// userRequest sets the user hash in values and makes a request. When the
+// API rejects the request, the hash is validated and, if it had expired,
+// renewed and the request retried once. Other errors, such as network or
+// decoding failures, are returned without a retry.
+func (s *session) userRequest(values url.Values, request func(url.Values) error) error {
+	hash, err := s.UserHash()
+	if err != nil {
+		return err
+	}
+	values.Set("userHash", hash)
+	err = request(values)
+	if _, ok := err.(*APIError); !ok || hash == "" {
+		return err
+	}
+	s.mu.Lock()
+	if s.userHash == hash {
+		s.checked = time.Time{}
+	}
+	renewed, herr := s.currentHash()
+	s.mu.Unlock()
+	if herr != nil || renewed == hash {
+		return err
+	}
+	values.Set("userHash", renewed)
+	return request(values)
+}
+
+// CheckUserHash reports whether a user hash is valid.
+func (c *Client) CheckUserHash(userHash string) (bool, error) {
+	r := &loginResponse{}
+	if err := c.makeRequest("checkUserHash", url.Values{"userHash": {userHash}}, r); err != nil {
+		return false, err
+	}
+	return r.Response == "valid", nil
+}
+
+func (c *Client) login(username, password string) (string, error) {
+	values := url.Values{"username": {username}, "password": {password}}
+	r := &loginResponse{}
+	if err := c.makeRequest("login", values, r); err != nil {
+		return "", err
+	}
+	hash := strings.TrimSpace(r.Response)
+	if hash == "" || hash == "INVALIDKEY" || strings.HasPrefix(hash, "ERROR") {
+		return "", fmt.Errorf("login: %s", hash)
+	}
+	return hash, nil
+}
+
+func (c *Client) userRequest(method string, values url.Values, result interface{}) error {
+	return c.session.userRequest(values, func(values url.Values) error {
+		return c.makeRequest(method, values, result)
+	})
+}
@@ -0,0 +1,90 @@
+package brickset
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c := NewClient("key", "user", "pass")
+	c.endpoint = server.URL
+	return c
+}
+
+func writeString(w http.ResponseWriter, s string) {
+	w.Write([]byte(`<string xmlns="https://brickset.com/api/">` + s + `</string>`))
+}
+
+func TestUserHashLifecycle(t *testing.T) {
+	hash := "old"
+	logins := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("apiKey") != "key" {
+			t.Errorf("unexpected apiKey %q", r.FormValue("apiKey"))
+		}
+		switch r.URL.Path {
+		case "/login":
+			logins++
+			hash = fmt.Sprintf("hash%d", logins)
+			writeString(w, hash)
+		case "/checkUserHash":
+			if r.FormValue("userHash") == hash {
+				writeString(w, "valid")
+			} else {
+				writeString(w, "invalid")
+			}
+		case "/getSet":
+			if r.FormValue("userHash") != hash {
+				http.Error(w, "invalid user hash", http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`<ArrayOfSets xmlns="https://brickset.com/api/"><sets><setID>22667</setID></sets></ArrayOfSets>`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	path := filepath.Join(t.TempDir(), "hash")
+	if err := ioutil.WriteFile(path, []byte("stale\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UseHashFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// The stale hash from the file is rejected by checkUserHash.
+	res, err := c.GetSet("22667")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Sets) != 1 || logins != 1 {
+		t.Errorf("expected 1 set after 1 login, but got %d after %d", len(res.Sets), logins)
+	}
+	if data, _ := ioutil.ReadFile(path); strings.TrimSpace(string(data)) != "hash1" {
+		t.Errorf("expected hash to be persisted, but got %q", data)
+	}
+
+	// The hash expires on the server and the failed request is retried.
+	hash = "expired"
+	if _, err := c.GetSet("22667"); err != nil {
+		t.Fatal(err)
+	}
+	if logins != 2 {
+		t.Errorf("expected renewal, but logged in %d times", logins)
+	}
+}
+
+func TestLoginError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeString(w, "INVALIDKEY")
+	})
+	if _, err := c.Login(); err == nil || err.Error() != "login: INVALIDKEY" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
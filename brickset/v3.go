@@ -0,0 +1,324 @@
+package brickset
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const endpointV3 = apiURL + "/v3.asmx"
+
+// ClientV3 makes requests to version 3 of the Brickset API, which takes
+// form-encoded parameters with a JSON params field and returns JSON. Like
+// Client, it holds the API key and the user hash, logging in lazily when the
+// hash is first needed and again when it expires.
+// See: https://brickset.com/article/52664/api-version-3-documentation
+type ClientV3 struct {
+	*session
+	c        *http.Client
+	endpoint string
+}
+
+// NewClientV3 creates a new Brickset API v3 client. username and password
+// may be empty for requests that do not access a user's collection.
+func NewClientV3(apiKey, username, password string) *ClientV3 {
+	c := &ClientV3{c: &http.Client{}, endpoint: endpointV3}
+	c.session = &session{apiKey: apiKey, username: username, password: password, loginFunc: c.login, checkFunc: c.CheckUserHash}
+	return c
+}
+
+// v3Response is the status included in every v3 response.
+type v3Response struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+func (r *v3Response) response() *v3Response { return r }
+
+type v3Result interface {
+	response() *v3Response
+}
+
+func (c *ClientV3) makeRequest(method string, values url.Values, result v3Result) error {
+	values.Set("apiKey", c.apiKey)
+	req, err := http.NewRequest("POST", c.endpoint+"/"+method, strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: status %s", method, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return err
+	}
+	if r := result.response(); r.Status != "success" {
+		return &APIError{method, r.Message}
+	}
+	return nil
+}
+
+// CheckKey checks that the client's API key is valid.
+func (c *ClientV3) CheckKey() error {
+	var r v3Response
+	return c.makeRequest("checkKey", url.Values{}, &r)
+}
+
+func (c *ClientV3) login(username, password string) (string, error) {
+	values := url.Values{"username": {username}, "password": {password}}
+	var r loginResponseV3
+	if err := c.makeRequest("login", values, &r); err != nil {
+		return "", err
+	}
+	return r.Hash, nil
+}
+
+type loginResponseV3 struct {
+	v3Response
+	Hash string `json:"hash"`
+}
+
+// CheckUserHash reports whether a user hash is valid.
+func (c *ClientV3) CheckUserHash(userHash string) (bool, error) {
+	var r v3Response
+	err := c.makeRequest("checkUserHash", url.Values{"userHash": {userHash}}, &r)
+	if _, ok := err.(*APIError); ok {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetKeyUsageStats returns the number of requests made with the client's
+// API key per day over the last 30 days.
+func (c *ClientV3) GetKeyUsageStats() ([]KeyUsage, error) {
+	var r keyUsageResponse
+	if err := c.makeRequest("getKeyUsageStats", url.Values{}, &r); err != nil {
+		return nil, err
+	}
+	return r.APIKeyUsage, nil
+}
+
+type keyUsageResponse struct {
+	v3Response
+	Matches     int        `json:"matches"`
+	APIKeyUsage []KeyUsage `json:"apiKeyUsage"`
+}
+
+// KeyUsage is the number of API requests made on a day.
+type KeyUsage struct {
+	DateStamp Time `json:"dateStamp"`
+	Count     int  `json:"count"`
+}
+
+// GetSet is used to get the details for a single set.
+func (c *ClientV3) GetSet(setID int) (*Set, error) {
+	sets, _, err := c.GetSets(SetParams{SetID: setID, ExtendedData: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return nil, fmt.Errorf("getSets: set %d not found", setID)
+	}
+	return &sets[0], nil
+}
+
+// GetSets searches for sets. It returns a page of sets and the total number
+// of matches. Collection details are only included when the client has a
+// username.
+func (c *ClientV3) GetSets(params SetParams) ([]Set, int, error) {
+	values, err := paramsValues(params)
+	if err != nil {
+		return nil, 0, err
+	}
+	var r setsResponse
+	if err := c.userRequest("getSets", values, &r); err != nil {
+		return nil, 0, err
+	}
+	return r.Sets, r.Matches, nil
+}
+
+func (c *ClientV3) userRequest(method string, values url.Values, result v3Result) error {
+	return c.session.userRequest(values, func(values url.Values) error {
+		return c.makeRequest(method, values, result)
+	})
+}
+
+// paramsValues encodes params as the JSON params field.
+func paramsValues(params interface{}) (url.Values, error) {
+	p, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return url.Values{"params": {string(p)}}, nil
+}
+
+type setsResponse struct {
+	v3Response
+	Matches int   `json:"matches"`
+	Sets    []Set `json:"sets"`
+}
+
+// SetParams are the search parameters of getSets. At least one of SetID,
+// Query, Theme, Subtheme, SetNumber, Year, Tag, Owned or Wanted must be set.
+// Theme, Subtheme, SetNumber and Year accept comma separated lists.
+type SetParams struct {
+	SetID        int    `json:"setID,omitempty"`
+	Query        string `json:"query,omitempty"`
+	Theme        string `json:"theme,omitempty"`
+	Subtheme     string `json:"subtheme,omitempty"`
+	SetNumber    string `json:"setNumber,omitempty"` // In the form "75192-1"
+	Year         string `json:"year,omitempty"`
+	Tag          string `json:"tag,omitempty"`
+	Owned        bool   `json:"owned,omitempty"`
+	Wanted       bool   `json:"wanted,omitempty"`
+	UpdatedSince string `json:"updatedSince,omitempty"` // In the form yyyy-mm-dd
+	OrderBy      string `json:"orderBy,omitempty"`      // e.g. "Number", "YearFromDESC", "Pieces", "Rating"
+	PageSize     int    `json:"pageSize,omitempty"`     // Defaults to 20, maximum 500
+	PageNumber   int    `json:"pageNumber,omitempty"`
+	ExtendedData bool   `json:"extendedData,omitempty"` // Include tags, notes and description
+}
+
+// Set is a single set returned by getSets. Numbers, prices and dimensions
+// that Brickset omits or leaves null are decoded as unknown.
+type Set struct {
+	SetID                int            `json:"setID"`
+	Number               string         `json:"number"`
+	NumberVariant        int            `json:"numberVariant"`
+	Name                 string         `json:"name"`
+	Year                 OptionalInt    `json:"year"`
+	Theme                string         `json:"theme"`
+	ThemeGroup           string         `json:"themeGroup"`
+	Subtheme             string         `json:"subtheme"`
+	Category             string         `json:"category"`
+	Released             bool           `json:"released"`
+	Pieces               OptionalInt    `json:"pieces"`
+	Minifigs             OptionalInt    `json:"minifigs"`
+	Image                SetImage       `json:"image"`
+	BricksetURL          string         `json:"bricksetURL"`
+	Collection           SetCollection  `json:"collection"`
+	Collections          SetCollections `json:"collections"`
+	LEGOCom              LEGOCom        `json:"LEGOCom"`
+	Rating               float64        `json:"rating"`
+	ReviewCount          int            `json:"reviewCount"`
+	PackagingType        string         `json:"packagingType"`
+	Availability         string         `json:"availability"`
+	InstructionsCount    int            `json:"instructionsCount"`
+	AdditionalImageCount int            `json:"additionalImageCount"`
+	AgeRange             AgeRange       `json:"ageRange"`
+	Dimensions           Dimensions     `json:"dimensions"`
+	Barcode              Barcode        `json:"barcode"`
+	ExtendedData         ExtendedData   `json:"extendedData"`
+	LastUpdated          Time           `json:"lastUpdated"`
+}
+
+// SetImage contains the URLs of the main image of a set.
+type SetImage struct {
+	ThumbnailURL string `json:"thumbnailURL"`
+	ImageURL     string `json:"imageURL"`
+}
+
+// SetCollection contains the user's collection details for a set.
+type SetCollection struct {
+	Owned    bool   `json:"owned"`
+	Wanted   bool   `json:"wanted"`
+	QtyOwned int    `json:"qtyOwned"`
+	Rating   int    `json:"rating"`
+	Notes    string `json:"notes"`
+}
+
+// SetCollections contains the number of users owning or wanting a set.
+type SetCollections struct {
+	OwnedBy  int `json:"ownedBy"`
+	WantedBy int `json:"wantedBy"`
+}
+
+// LEGOCom contains the retail details of a set per LEGO.com region.
+type LEGOCom struct {
+	US LEGOComDetails `json:"US"`
+	UK LEGOComDetails `json:"UK"`
+	CA LEGOComDetails `json:"CA"`
+	DE LEGOComDetails `json:"DE"`
+}
+
+// LEGOComDetails contains the retail price and availability dates of a set
+// in a region. Prices are in the currency of the region.
+type LEGOComDetails struct {
+	RetailPrice        Price `json:"retailPrice"`
+	DateFirstAvailable Time  `json:"dateFirstAvailable"`
+	DateLastAvailable  Time  `json:"dateLastAvailable"`
+}
+
+// AgeRange is the recommended age range of a set.
+type AgeRange struct {
+	Min OptionalInt `json:"min"`
+	Max OptionalInt `json:"max"`
+}
+
+// Dimensions are the package dimensions in centimeters and weight in
+// kilograms.
+type Dimensions struct {
+	Height OptionalFloat `json:"height"`
+	Width  OptionalFloat `json:"width"`
+	Depth  OptionalFloat `json:"depth"`
+	Weight OptionalFloat `json:"weight"`
+}
+
+// Barcode contains the barcodes of a set.
+type Barcode struct {
+	EAN string `json:"EAN"`
+	UPC string `json:"UPC"`
+}
+
+// ExtendedData is only returned when SetParams.ExtendedData is set.
+type ExtendedData struct {
+	Notes       string   `json:"notes"`
+	Tags        []string `json:"tags"`
+	Description string   `json:"description"`
+}
+
+// Time is a timestamp returned by Brickset. Brickset omits the time zone
+// on most timestamps, in which case UTC is assumed.
+type Time struct {
+	time.Time
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func parseTime(s string) (Time, error) {
+	if s == "" {
+		return Time{}, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return Time{t}, nil
+		}
+	}
+	return Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// UnmarshalJSON decodes a timestamp with or without a time zone.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Time{}
+		return nil
+	}
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid time %s", data)
+	}
+	*t, err = parseTime(s)
+	return err
+}
@@ -0,0 +1,146 @@
+package brickset
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+// newTestClientV3 creates a client for user "user" with API key "key". The
+// server logs in with user hash "hash" and passes other requests to handler.
+func newTestClientV3(t *testing.T, handler http.HandlerFunc) *ClientV3 {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("apiKey") != "key" {
+			t.Errorf("unexpected apiKey %q", r.FormValue("apiKey"))
+		}
+		switch r.URL.Path {
+		case "/login":
+			w.Write([]byte(`{"status":"success","hash":"hash"}`))
+		case "/checkUserHash":
+			if r.FormValue("userHash") == "hash" {
+				w.Write([]byte(`{"status":"success"}`))
+			} else {
+				w.Write([]byte(`{"status":"error","message":"Invalid user hash"}`))
+			}
+		default:
+			handler(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	c := NewClientV3("key", "user", "pass")
+	c.endpoint = server.URL
+	return c
+}
+
+func TestGetSetsV3(t *testing.T) {
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/getSets" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.FormValue("apiKey") != "key" || r.FormValue("userHash") != "hash" {
+			t.Errorf("unexpected credentials %v", r.Form)
+		}
+		var params SetParams
+		if err := json.Unmarshal([]byte(r.FormValue("params")), &params); err != nil {
+			t.Error(err)
+		}
+		if params.Query != "Millennium Falcon & more" || params.PageSize != 5 {
+			t.Errorf("unexpected params %+v", params)
+		}
+		w.Write([]byte(`{"status":"success","matches":1,"sets":[{"setID":26725,"number":"75192","numberVariant":1,"name":"Millennium Falcon","year":2017,"theme":"Star Wars","themeGroup":"Licensed","subtheme":"Ultimate Collector Series","category":"Normal","released":true,"pieces":7541,"minifigs":8,"image":{"thumbnailURL":"https://images.brickset.com/sets/small/75192-1.jpg","imageURL":"https://images.brickset.com/sets/images/75192-1.jpg"},"bricksetURL":"https://brickset.com/sets/75192-1","collection":{"owned":true,"wanted":false,"qtyOwned":1,"rating":5,"notes":""},"collections":{"ownedBy":12000,"wantedBy":9000},"LEGOCom":{"US":{"retailPrice":799.99,"dateFirstAvailable":"2017-10-01T00:00:00Z"},"UK":{"retailPrice":649.99},"CA":{},"DE":{}},"rating":4.6,"reviewCount":20,"packagingType":"Box","availability":"LEGO exclusive","instructionsCount":4,"additionalImageCount":50,"ageRange":{"min":16},"dimensions":{"height":58.6,"width":48.2,"depth":33.5,"weight":16.6},"barcode":{"EAN":"5702015869935"},"extendedData":{},"lastUpdated":"2020-04-23T07:47:56.273"}]}`))
+	})
+	sets, matches, err := c.GetSets(SetParams{Query: "Millennium Falcon & more", PageSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matches != 1 || len(sets) != 1 {
+		t.Fatalf("expected 1 set, but got %d of %d", len(sets), matches)
+	}
+	s := sets[0]
+	if s.Year.Value != 2017 || s.Pieces.Value != 7541 || s.AgeRange.Min.Value != 16 || s.AgeRange.Max.Known || !s.Collection.Owned {
+		t.Errorf("unexpected set %+v", s)
+	}
+	if us := s.RetailPrice(CurrencyUS); us != (Price{money.MustParseDecimal("799.99"), "USD", true}) {
+		t.Errorf("unexpected US price %v", us)
+	}
+	if ca := s.RetailPrice(CurrencyCA); ca.Known || ca.Currency != "CAD" {
+		t.Errorf("expected unknown CAD price, but got %v", ca)
+	}
+	if s.Dimensions.Height.Value != 58.6 || !s.Dimensions.Weight.Known {
+		t.Errorf("unexpected dimensions %+v", s.Dimensions)
+	}
+	if _, ok := s.PricePerPiece(CurrencyEU); ok {
+		t.Error("expected unknown EUR price per piece")
+	}
+	if want := time.Date(2020, 4, 23, 7, 47, 56, 273000000, time.UTC); !s.LastUpdated.Equal(want) {
+		t.Errorf("expected lastUpdated %v, but got %v", want, s.LastUpdated)
+	}
+}
+
+func TestV3Error(t *testing.T) {
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"error","message":"Invalid API key"}`))
+	})
+	if err := c.CheckKey(); err == nil || err.Error() != "checkKey: Invalid API key" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestGetKeyUsageStats(t *testing.T) {
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"success","matches":2,"apiKeyUsage":[{"dateStamp":"2020-05-06T00:00:00Z","count":12},{"dateStamp":"2020-05-05T00:00:00Z","count":3}]}`))
+	})
+	usage, err := c.GetKeyUsageStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 2 || usage[0].Count != 12 || usage[0].DateStamp.Day() != 6 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
+
+func TestUserHashRenewalV3(t *testing.T) {
+	requests := 0
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.FormValue("userHash") != "hash" {
+			w.Write([]byte(`{"status":"error","message":"Invalid user hash"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"success","matches":0,"sets":[]}`))
+	})
+	c.SetUserHash("expired")
+	if _, _, err := c.GetSets(SetParams{Owned: true}); err != nil {
+		t.Fatal(err)
+	}
+	if hash, _ := c.UserHash(); hash != "hash" || requests != 1 {
+		t.Errorf("expected renewed hash before 1 request, but got %q after %d", hash, requests)
+	}
+
+	// The server expires the hash after it was validated.
+	c.session.userHash = "expired"
+	if _, _, err := c.GetSets(SetParams{Owned: true}); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("expected a retry, but made %d requests", requests)
+	}
+}
+
+func TestNoRetryOnDecodeErrorV3(t *testing.T) {
+	requests := 0
+	c := newTestClientV3(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`not json`))
+	})
+	if _, _, err := c.GetSets(SetParams{Owned: true}); err == nil {
+		t.Fatal("expected decode error")
+	}
+	if requests != 1 {
+		t.Errorf("expected no retry, but made %d requests", requests)
+	}
+}
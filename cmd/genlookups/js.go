@@ -0,0 +1,318 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// jsParser reads the subset of JavaScript literal syntax used by the
+// variable declarations in allVars.js: arrays, objects with quoted or bare
+// keys, single or double quoted strings, numbers, booleans and null.
+type jsParser struct {
+	src string
+	pos int
+}
+
+// findVar locates the declaration of a top level variable and parses its
+// value.
+func findVar(src, name string) (interface{}, error) {
+	re := regexp.MustCompile(`(?:^|[^\w$.])` + regexp.QuoteMeta(name) + `\s*=\s*`)
+	loc := re.FindStringIndex(src)
+	if loc == nil {
+		return nil, fmt.Errorf("%s not found", name)
+	}
+	p := &jsParser{src: src, pos: loc[1]}
+	v, err := p.parseValue()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return v, nil
+}
+
+func (p *jsParser) parseValue() (interface{}, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseObject()
+	case c == '\'' || c == '"':
+		return p.parseString()
+	case c == '-' || c == '.' || ('0' <= c && c <= '9'):
+		return p.parseNumber()
+	default:
+		ident := p.parseIdent()
+		switch ident {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null", "undefined":
+			return nil, nil
+		}
+		return nil, p.errorf("unexpected %q", ident)
+	}
+}
+
+func (p *jsParser) parseArray() ([]interface{}, error) {
+	p.pos++ // [
+	var values []interface{}
+	for {
+		p.skipSpace()
+		if p.consume(']') {
+			return values, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		p.skipSpace()
+		if p.consume(']') {
+			return values, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *jsParser) parseObject() (map[string]interface{}, error) {
+	p.pos++ // {
+	obj := make(map[string]interface{})
+	for {
+		p.skipSpace()
+		if p.consume('}') {
+			return obj, nil
+		}
+		var key string
+		if c := p.peek(); c == '\'' || c == '"' {
+			s, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			key = s
+		} else if key = p.parseIdent(); key == "" {
+			return nil, p.errorf("expected object key")
+		}
+		p.skipSpace()
+		if !p.consume(':') {
+			return nil, p.errorf("expected :")
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = v
+		p.skipSpace()
+		if p.consume('}') {
+			return obj, nil
+		}
+		if !p.consume(',') {
+			return nil, p.errorf("expected , or }")
+		}
+	}
+}
+
+func (p *jsParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.pos >= len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.src[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 16)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *jsParser) parseNumber() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.src) && strings.IndexByte("+-.0123456789eE", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", p.src[start:p.pos])
+	}
+	return n, nil
+}
+
+func (p *jsParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c != '_' && c != '$' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *jsParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		case '/':
+			if strings.HasPrefix(p.src[p.pos:], "//") {
+				if i := strings.IndexByte(p.src[p.pos:], '\n'); i >= 0 {
+					p.pos += i + 1
+				} else {
+					p.pos = len(p.src)
+				}
+			} else if strings.HasPrefix(p.src[p.pos:], "/*") {
+				if i := strings.Index(p.src[p.pos+2:], "*/"); i >= 0 {
+					p.pos += i + 4
+				} else {
+					p.pos = len(p.src)
+				}
+			} else {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *jsParser) consume(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *jsParser) errorf(format string, args ...interface{}) error {
+	line := 1 + strings.Count(p.src[:p.pos], "\n")
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// itemTypeName is a case of blc_CatalogItem.getItemTypeName in jslegacy.
+type itemTypeName struct {
+	Type     rune
+	Singular string
+	Plural   string
+}
+
+var itemTypeNameRe = regexp.MustCompile(`case\s*['"](\w)['"]\s*:|return\s*(?:(!?)\s*[\w$.]+\s*\?\s*['"]([^'"]*)['"]\s*:\s*['"]([^'"]*)['"]|['"]([^'"]*)['"])`)
+
+// itemTypeNameDefRe matches the definition of getItemTypeName as an object
+// property, an assignment, a function declaration or a method, but not a call.
+var itemTypeNameDefRe = regexp.MustCompile(`\bgetItemTypeName\s*[:=]\s*function\b|\bfunction\s+getItemTypeName\s*\(|\bgetItemTypeName\s*\([\w$\s,]*\)\s*\{`)
+
+// findItemTypeNames extracts the switch cases of getItemTypeName from the
+// jslegacy bundle.
+func findItemTypeNames(src string) ([]itemTypeName, error) {
+	loc := itemTypeNameDefRe.FindStringIndex(src)
+	if loc == nil {
+		return nil, fmt.Errorf("getItemTypeName definition not found")
+	}
+	body, err := functionBody(src[loc[0]:])
+	if err != nil {
+		return nil, fmt.Errorf("getItemTypeName: %v", err)
+	}
+	var names []itemTypeName
+	seen := make(map[rune]bool)
+	var pending []rune
+	for _, m := range itemTypeNameRe.FindAllStringSubmatch(body, -1) {
+		if m[1] != "" {
+			pending = append(pending, []rune(strings.ToUpper(m[1]))[0])
+			continue
+		}
+		singular, plural := m[5], m[5]
+		if m[5] == "" {
+			plural, singular = m[3], m[4]
+			if m[2] == "!" {
+				plural, singular = singular, plural
+			}
+		}
+		for _, t := range pending {
+			if !seen[t] {
+				seen[t] = true
+				names = append(names, itemTypeName{t, singular, plural})
+			}
+		}
+		pending = nil
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("getItemTypeName: no cases found")
+	}
+	return names, nil
+}
+
+// functionBody returns the brace-delimited body of the first function in src.
+func functionBody(src string) (string, error) {
+	start := strings.IndexByte(src, '{')
+	if start < 0 {
+		return "", fmt.Errorf("function body not found")
+	}
+	depth := 0
+	var quote byte
+	for i := start; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return src[start+1 : i], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unterminated function body")
+}
@@ -0,0 +1,405 @@
+// Command genlookups generates bricklinkuser/lookups.go from saved copies of
+// https://www.bricklink.com/js/allVars.js and the jslegacy bundle.
+//
+// Usage:
+//
+//	genlookups -allvars allVars.js -jslegacy jslegacy.js [-date 2006-01-02] [-o lookups.go]
+//
+// The inputs are not kept in the repository. When -allvars or -jslegacy is
+// omitted, it defaults to $BRICKLINK_ALLVARS or $BRICKLINK_JSLEGACY, which is
+// how go generate in bricklinkuser finds them.
+//
+// The output depends only on the inputs, so regenerating from the same files
+// is a no-op. A report of added, removed and renamed colors and categories
+// relative to the previous output is printed to stderr.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type fieldKind int
+
+const (
+	intField fieldKind = iota
+	stringField
+)
+
+type field struct {
+	Name string // Go field name
+	Key  string // Key in allVars.js, also used as the JSON tag
+	Kind fieldKind
+}
+
+type table struct {
+	JSName string // Variable in allVars.js
+	Var    string // Go variable
+	Type   string // Go element type
+	Fields []field
+}
+
+var tables = []table{
+	{"_varCurrencyList", "currencyList", "Currency", []field{
+		{"CurrencyID", "idCurrency", intField},
+		{"CurrencyName", "strCurrencyName", stringField},
+		{"CurrencyCode", "strCurrencyCode", stringField},
+	}},
+	{"_varPmtMethodList", "paymentMethodList", "PaymentMethod", []field{
+		{"PaymentMethodID", "idPaymentMethod", intField},
+		{"MethodName", "strMethodName", stringField},
+	}},
+	{"_varCountryList", "countryList", "Country", []field{
+		{"CountryID", "idCountry", stringField},
+		{"CountryName", "strCountryName", stringField},
+	}},
+	{"_varLanguageList", "languageList", "Language", []field{
+		{"LanguageCode", "codeLanguage", stringField},
+		{"LanguageName", "strLanguageName", stringField},
+	}},
+	{"_varPromoList", "promoList", "Promo", []field{
+		{"PromoID", "idPromo", intField},
+		{"PromoName", "strPromoName", stringField},
+	}},
+	{"_varArrayCategory", "categoryList", "Category", []field{
+		{"CategoryID", "idCategory", intField},
+		{"CategoryName", "strCatName", stringField},
+		{"Types", "types", stringField},
+		{"BrandID", "idBrand", intField},
+	}},
+	{"_varColorList", "colorList", "Color", []field{
+		{"ColorID", "idColor", intField},
+		{"ColorName", "strColorName", stringField},
+		{"Group", "group", intField},
+		{"RGB", "rgb", stringField},
+	}},
+}
+
+const (
+	yearVar     = "_varYearList"
+	brandVar    = "_varBrandList"
+	categoryVar = "_varArrayCategory"
+)
+
+// brandNames names the brands referenced by idBrand in _varArrayCategory
+// when allVars.js has no _varBrandList. An id missing from here fails
+// generation rather than producing a brand without a name.
+var brandNames = map[int]string{
+	1000: "LEGO",
+	1001: "BrickArms",
+}
+
+func main() {
+	allVarsFile := flag.String("allvars", os.Getenv("BRICKLINK_ALLVARS"), "saved allVars.js (default $BRICKLINK_ALLVARS)")
+	jslegacyFile := flag.String("jslegacy", os.Getenv("BRICKLINK_JSLEGACY"), "saved jslegacy bundle (default $BRICKLINK_JSLEGACY)")
+	date := flag.String("date", "", "date the inputs were downloaded, for the header comment")
+	out := flag.String("o", "lookups.go", "output file")
+	pkg := flag.String("pkg", "bricklinkuser", "package name of the output")
+	flag.Parse()
+	if *allVarsFile == "" || *jslegacyFile == "" {
+		fmt.Fprintln(os.Stderr, "genlookups: set -allvars and -jslegacy, or $BRICKLINK_ALLVARS and $BRICKLINK_JSLEGACY, to saved copies of the BrickLink scripts")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	allVars, err := ioutil.ReadFile(*allVarsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	jslegacy, err := ioutil.ReadFile(*jslegacyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(string(allVars), string(jslegacy), *pkg, *date)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if old, err := ioutil.ReadFile(*out); err == nil {
+		report(os.Stderr, string(old), string(src))
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate renders lookups.go from the contents of allVars.js and jslegacy.
+func generate(allVars, jslegacy, pkg, date string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by genlookups from allVars.js and jslegacy. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if date != "" {
+		fmt.Fprintf(&b, "// Converted from https://www.bricklink.com/js/allVars.js on %s\n\n", date)
+	} else {
+		fmt.Fprintf(&b, "// Converted from https://www.bricklink.com/js/allVars.js\n\n")
+	}
+
+	for _, t := range tables {
+		v, err := findVar(allVars, t.JSName)
+		if err != nil {
+			return nil, err
+		}
+		rows, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: expected array", t.JSName)
+		}
+		fmt.Fprintf(&b, "type %s struct {\n", t.Type)
+		for _, f := range t.Fields {
+			kind := "int"
+			if f.Kind == stringField {
+				kind = "string"
+			}
+			fmt.Fprintf(&b, "\t%s %s `json:%q`\n", f.Name, kind, f.Key)
+		}
+		fmt.Fprintf(&b, "}\n\n")
+		fmt.Fprintf(&b, "// Converted from %s in allVars.js\n", t.JSName)
+		fmt.Fprintf(&b, "var %s = []%s{\n", t.Var, t.Type)
+		for i, row := range rows {
+			obj, ok := row.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s[%d]: expected object", t.JSName, i)
+			}
+			b.WriteString("\t{")
+			for j, f := range t.Fields {
+				if j != 0 {
+					b.WriteString(", ")
+				}
+				lit, err := literal(obj[f.Key], f.Kind)
+				if err != nil {
+					return nil, fmt.Errorf("%s[%d].%s: %v", t.JSName, i, f.Key, err)
+				}
+				fmt.Fprintf(&b, "%s: %s", f.Name, lit)
+			}
+			b.WriteString("},\n")
+		}
+		fmt.Fprintf(&b, "}\n\n")
+	}
+
+	brands, source, err := findBrands(allVars)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&b, "type Brand struct {\n\tBrandID int `json:\"idBrand\"`\n\tBrandName string `json:\"strBrandName\"`\n}\n\n")
+	fmt.Fprintf(&b, "// %s\n", source)
+	fmt.Fprintf(&b, "var brandList = []Brand{\n")
+	for _, br := range brands {
+		name, err := literal(br.Name, stringField)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "\t{BrandID: %d, BrandName: %s},\n", br.ID, name)
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	v, err := findVar(allVars, yearVar)
+	if err != nil {
+		return nil, err
+	}
+	years, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected array", yearVar)
+	}
+	fmt.Fprintf(&b, "// Converted from %s in allVars.js\n", yearVar)
+	fmt.Fprintf(&b, "var yearList = []int{\n")
+	for i, y := range years {
+		lit, err := literal(y, intField)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %v", yearVar, i, err)
+		}
+		fmt.Fprintf(&b, "\t%s,\n", lit)
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	names, err := findItemTypeNames(jslegacy)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(&b, "type itemTypeName struct {\n\tType     rune\n\tSingular string\n\tPlural   string\n}\n\n")
+	fmt.Fprintf(&b, "// Converted from blc_CatalogItem.getItemTypeName in jslegacy\n")
+	fmt.Fprintf(&b, "var itemTypeNameList = []itemTypeName{\n")
+	for _, n := range names {
+		fmt.Fprintf(&b, "\t{Type: %q, Singular: %q, Plural: %q},\n", n.Type, n.Singular, n.Plural)
+	}
+	fmt.Fprintf(&b, "}\n")
+
+	return format.Source(b.Bytes())
+}
+
+type brand struct {
+	ID   int
+	Name string
+}
+
+// findBrands reads _varBrandList if allVars.js declares it. Otherwise it
+// collects the brand ids referenced by categories, in ascending order, and
+// names them from brandNames. The returned source describes where the table
+// came from, for the generated comment.
+func findBrands(allVars string) ([]brand, string, error) {
+	if v, err := findVar(allVars, brandVar); err == nil {
+		rows, ok := v.([]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("%s: expected array", brandVar)
+		}
+		brands := make([]brand, len(rows))
+		for i, row := range rows {
+			obj, ok := row.(map[string]interface{})
+			if !ok {
+				return nil, "", fmt.Errorf("%s[%d]: expected object", brandVar, i)
+			}
+			id, err := literal(obj["idBrand"], intField)
+			if err != nil {
+				return nil, "", fmt.Errorf("%s[%d].idBrand: %v", brandVar, i, err)
+			}
+			name, ok := obj["strBrandName"].(string)
+			if !ok {
+				return nil, "", fmt.Errorf("%s[%d].strBrandName: expected string", brandVar, i)
+			}
+			brands[i].ID, _ = strconv.Atoi(id)
+			brands[i].Name = name
+		}
+		return brands, "Converted from " + brandVar + " in allVars.js", nil
+	}
+
+	v, err := findVar(allVars, categoryVar)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, ok := v.([]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("%s: expected array", categoryVar)
+	}
+	seen := make(map[int]bool)
+	var ids []int
+	for i, row := range rows {
+		obj, ok := row.(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("%s[%d]: expected object", categoryVar, i)
+		}
+		lit, err := literal(obj["idBrand"], intField)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s[%d].idBrand: %v", categoryVar, i, err)
+		}
+		id, _ := strconv.Atoi(lit)
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	brands := make([]brand, len(ids))
+	for i, id := range ids {
+		name, ok := brandNames[id]
+		if !ok {
+			return nil, "", fmt.Errorf("%s: brand %d has no name; add it to brandNames", categoryVar, id)
+		}
+		brands[i] = brand{id, name}
+	}
+	return brands, "Brands referenced by idBrand in " + categoryVar + " in allVars.js", nil
+}
+
+// literal formats a parsed JavaScript value as a Go literal of the given kind.
+func literal(v interface{}, kind fieldKind) (string, error) {
+	switch kind {
+	case intField:
+		switch n := v.(type) {
+		case float64:
+			if n != math.Trunc(n) {
+				return "", fmt.Errorf("%v is not an integer", n)
+			}
+			return strconv.FormatInt(int64(n), 10), nil
+		case string:
+			i, err := strconv.Atoi(n)
+			if err != nil {
+				return "", err
+			}
+			return strconv.Itoa(i), nil
+		case nil:
+			return "0", nil
+		}
+	case stringField:
+		switch s := v.(type) {
+		case string:
+			if strings.Contains(s, `"`) && strconv.CanBackquote(s) {
+				return "`" + s + "`", nil
+			}
+			return strconv.Quote(s), nil
+		case float64:
+			return strconv.Quote(strconv.FormatFloat(s, 'f', -1, 64)), nil
+		case nil:
+			return `""`, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected value %v", v)
+}
+
+// stringLitRe matches a double-quoted or backquoted Go string literal, as
+// written by literal.
+const stringLitRe = `"(?:[^"\\]|\\.)*"|` + "`[^`]*`"
+
+var (
+	colorRe    = regexp.MustCompile(`\{ColorID: (\d+), ColorName: (` + stringLitRe + `)`)
+	categoryRe = regexp.MustCompile(`\{CategoryID: (\d+), CategoryName: (` + stringLitRe + `)`)
+)
+
+// report prints the colors and categories that were added, removed or
+// renamed between two versions of lookups.go.
+func report(w io.Writer, old, new string) {
+	reportTable(w, "color", colorRe, old, new)
+	reportTable(w, "category", categoryRe, old, new)
+}
+
+func reportTable(w io.Writer, kind string, re *regexp.Regexp, old, new string) {
+	oldNames, newNames := scanNames(re, old), scanNames(re, new)
+	var ids []int
+	for id := range oldNames {
+		ids = append(ids, id)
+	}
+	for id := range newNames {
+		if _, ok := oldNames[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	var added, removed, renamed int
+	for _, id := range ids {
+		oldName, inOld := oldNames[id]
+		newName, inNew := newNames[id]
+		switch {
+		case !inOld:
+			fmt.Fprintf(w, "+ %s %d %s\n", kind, id, newName)
+			added++
+		case !inNew:
+			fmt.Fprintf(w, "- %s %d %s\n", kind, id, oldName)
+			removed++
+		case oldName != newName:
+			fmt.Fprintf(w, "~ %s %d %s -> %s\n", kind, id, oldName, newName)
+			renamed++
+		}
+	}
+	fmt.Fprintf(w, "%s: %d added, %d removed, %d renamed\n", kind, added, removed, renamed)
+}
+
+func scanNames(re *regexp.Regexp, src string) map[int]string {
+	names := make(map[int]string)
+	for _, m := range re.FindAllStringSubmatch(src, -1) {
+		id, _ := strconv.Atoi(m[1])
+		name, err := strconv.Unquote(m[2])
+		if err != nil {
+			name = m[2]
+		}
+		names[id] = name
+	}
+	return names
+}
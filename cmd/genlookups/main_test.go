@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testAllVars = `
+var _varCurrencyList=[{idCurrency:1,strCurrencyName:'US Dollar',strCurrencyCode:'USD'}];
+var _varPmtMethodList=[{idPaymentMethod:11,strMethodName:"PayPal"}];
+var _varCountryList=[{idCountry:'CI',strCountryName:'Cote D\'Ivoire'}];
+var _varLanguageList=[{codeLanguage:'EN',strLanguageName:'English'}];
+var _varPromoList=[{idPromo:126,strPromoName:'Toys "R" Us'}, {idPromo:166,strPromoName:'Citroën'}];
+var _varArrayCategory=[{idCategory:5,strCatName:'Brick',types:'P',idBrand:1000}];
+var _varColorList=[{idColor:0,strColorName:'(Not Applicable)',group:0,rgb:''},{idColor:11,strColorName:'Black',group:1,rgb:'212121'}];
+var _varYearList=[2020,2019];
+`
+
+const testJSLegacy = `var blc_CatalogItem={getItemTypeName:function(a,b){switch(a){case"P":case"p":return b?"Parts":"Part";case"G":return"Gear";case"O":return!b?"Original Box":"Original Boxes";default:return""}}};`
+
+func TestGenerate(t *testing.T) {
+	src, err := generate(testAllVars, testJSLegacy, "bricklinkuser", "2020-01-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Code generated by genlookups from allVars.js and jslegacy. DO NOT EDIT.",
+		"// Converted from https://www.bricklink.com/js/allVars.js on 2020-01-02",
+		`{CurrencyID: 1, CurrencyName: "US Dollar", CurrencyCode: "USD"},`,
+		`{CountryID: "CI", CountryName: "Cote D'Ivoire"},`,
+		"{PromoID: 126, PromoName: `Toys \"R\" Us`},",
+		`{PromoID: 166, PromoName: "Citroën"},`,
+		`{CategoryID: 5, CategoryName: "Brick", Types: "P", BrandID: 1000},`,
+		`{ColorID: 11, ColorName: "Black", Group: 1, RGB: "212121"},`,
+		`{BrandID: 1000, BrandName: "LEGO"},`,
+		"var yearList = []int{\n\t2020,\n\t2019,\n}",
+		`{Type: 'P', Singular: "Part", Plural: "Parts"},`,
+		`{Type: 'G', Singular: "Gear", Plural: "Gear"},`,
+		`{Type: 'O', Singular: "Original Box", Plural: "Original Boxes"},`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+
+	again, err := generate(testAllVars, testJSLegacy, "bricklinkuser", "2020-01-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, again) {
+		t.Error("output is not deterministic")
+	}
+}
+
+func TestGenerateBrands(t *testing.T) {
+	allVars := testAllVars + "var _varBrandList=[{idBrand:1000,strBrandName:'LEGO'},{idBrand:1002,strBrandName:'Mega Bloks'}];\n"
+	src, err := generate(allVars, testJSLegacy, "bricklinkuser", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), `{BrandID: 1002, BrandName: "Mega Bloks"},`) {
+		t.Errorf("output does not contain brands from %s", brandVar)
+	}
+
+	allVars = strings.Replace(testAllVars, "idBrand:1000", "idBrand:1003", 1)
+	if _, err := generate(allVars, testJSLegacy, "bricklinkuser", ""); err == nil {
+		t.Error("expected error for unnamed brand")
+	}
+}
+
+func TestFindItemTypeNamesSkipsCalls(t *testing.T) {
+	src := `if(blc_CatalogItem.getItemTypeName(t)){switch(t){case"Z":return"Zed"}}` + testJSLegacy
+	names, err := findItemTypeNames(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 || names[0].Singular != "Part" {
+		t.Errorf("unexpected names %v", names)
+	}
+}
+
+func TestReportBackquoted(t *testing.T) {
+	old := "{CategoryID: 7, CategoryName: `Toys \"R\" Us`, Types: \"S\", BrandID: 1000},"
+	new := "{CategoryID: 7, CategoryName: `Toys \"R\" Us Exclusive`, Types: \"S\", BrandID: 1000},"
+	var b bytes.Buffer
+	report(&b, old, new)
+	if !strings.Contains(b.String(), `~ category 7 Toys "R" Us -> Toys "R" Us Exclusive`) {
+		t.Errorf("backquoted rename not reported:\n%s", b.String())
+	}
+}
+
+func TestReport(t *testing.T) {
+	old := `{ColorID: 11, ColorName: "Black", Group: 1, RGB: "212121"},
+{ColorID: 12, ColorName: "Trans-Clear", Group: 2, RGB: "EEEEEE"},
+{CategoryID: 5, CategoryName: "Brick", Types: "P", BrandID: 1000},`
+	new := `{ColorID: 11, ColorName: "Black", Group: 1, RGB: "212121"},
+{ColorID: 12, ColorName: "Trans Clear", Group: 2, RGB: "EEEEEE"},
+{ColorID: 13, ColorName: "Trans-Black", Group: 2, RGB: "635F52"},`
+	var b bytes.Buffer
+	report(&b, old, new)
+	want := `~ color 12 Trans-Clear -> Trans Clear
++ color 13 Trans-Black
+color: 1 added, 0 removed, 1 renamed
+- category 5 Brick
+category: 0 added, 1 removed, 0 renamed
+`
+	if b.String() != want {
+		t.Errorf("expected report\n%s\nbut got\n%s", want, b.String())
+	}
+}
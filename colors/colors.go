@@ -0,0 +1,304 @@
+// Package colors relates the color models of BrickLink and LEGO.
+//
+// The canonical color ID is the BrickLink color ID, since the BrickLink
+// catalog covers the most colors. LEGO color IDs and names, including the
+// abbreviated names used by Bricks & Pieces, are mapped onto it.
+package colors
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+	"github.com/andrewarchi/brick-apis/bricklinkuser"
+	"github.com/andrewarchi/brick-apis/legobap"
+)
+
+// ID is a canonical color ID. It is equal to the BrickLink color ID.
+type ID int
+
+// Color is a color in the canonical model.
+type Color struct {
+	ID        ID       // Canonical ID, equal to the BrickLink color ID
+	Name      string   // BrickLink color name
+	RGB       RGB      // BrickLink RGB value
+	Group     Group    // BrickLink color group
+	LegoIDs   []int    // LEGO color IDs
+	LegoNames []string // LEGO color names, including Bricks & Pieces abbreviations
+}
+
+// Group is a BrickLink color group as in bricklinkuser.Color.Group.
+type Group int
+
+// BrickLink color groups.
+const (
+	GroupNone        Group = 0
+	GroupSolid       Group = 1
+	GroupTransparent Group = 2
+	GroupChrome      Group = 3
+	GroupPearl       Group = 4
+	GroupMetallic    Group = 6
+	GroupMilky       Group = 7
+	GroupGlitter     Group = 8
+	GroupSpeckle     Group = 9
+	GroupModulex     Group = 10
+	GroupBrickArms   Group = 11
+)
+
+var groupsByType = map[bricklinkstore.ColorType]Group{
+	bricklinkstore.ColorTypeSolid:       GroupSolid,
+	bricklinkstore.ColorTypeTransparent: GroupTransparent,
+	bricklinkstore.ColorTypeChrome:      GroupChrome,
+	bricklinkstore.ColorTypePearl:       GroupPearl,
+	bricklinkstore.ColorTypeMetallic:    GroupMetallic,
+	bricklinkstore.ColorTypeMilky:       GroupMilky,
+	bricklinkstore.ColorTypeGlitter:     GroupGlitter,
+	bricklinkstore.ColorTypeSpeckle:     GroupSpeckle,
+	bricklinkstore.ColorTypeModulex:     GroupModulex,
+	bricklinkstore.ColorTypeBrickArms:   GroupBrickArms,
+}
+
+// GroupOf returns the group for a BrickLink store API color type.
+func GroupOf(colorType bricklinkstore.ColorType) Group {
+	return groupsByType[colorType]
+}
+
+// RGB is a 24-bit color value.
+type RGB struct {
+	R, G, B uint8
+}
+
+// ParseRGB parses a hexadecimal color code such as "05131D" or "#05131d".
+func ParseRGB(hex string) (RGB, error) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) != 6 {
+		return RGB{}, fmt.Errorf("colors: invalid RGB %q", hex)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("colors: invalid RGB %q", hex)
+	}
+	return RGB{uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+}
+
+// Hex formats the color as an uppercase hexadecimal code without a leading #.
+func (c RGB) Hex() string {
+	return fmt.Sprintf("%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// Distance approximates the perceptual distance between two colors using
+// the "redmean" weighted Euclidean distance.
+func (c RGB) Distance(o RGB) float64 {
+	rMean := (float64(c.R) + float64(o.R)) / 2
+	dr := float64(c.R) - float64(o.R)
+	dg := float64(c.G) - float64(o.G)
+	db := float64(c.B) - float64(o.B)
+	return math.Sqrt((2+rMean/256)*dr*dr + 4*dg*dg + (2+(255-rMean)/256)*db*db)
+}
+
+var (
+	indexOnce   sync.Once
+	colorList   []Color
+	colorsByID  map[ID]*Color
+	colorsByBL  map[string]*Color
+	colorsByLID map[int]*Color
+	colorsByLN  map[string]*Color
+)
+
+func index() {
+	indexOnce.Do(func() {
+		blColors := bricklinkuser.Colors()
+		colorList = make([]Color, 0, len(blColors))
+		for _, c := range blColors {
+			rgb, _ := ParseRGB(c.RGB)
+			colorList = append(colorList, Color{
+				ID:    ID(c.ColorID),
+				Name:  c.ColorName,
+				RGB:   rgb,
+				Group: Group(c.Group),
+			})
+		}
+		colorsByID = make(map[ID]*Color, len(colorList))
+		colorsByBL = make(map[string]*Color, len(colorList))
+		for i := range colorList {
+			colorsByID[colorList[i].ID] = &colorList[i]
+			colorsByBL[normalizeName(colorList[i].Name)] = &colorList[i]
+		}
+		colorsByLID = make(map[int]*Color, len(legoColorList))
+		colorsByLN = make(map[string]*Color, len(legoColorList))
+		for _, lc := range legoColorList {
+			c, ok := colorsByID[lc.BrickLinkID]
+			if !ok {
+				continue
+			}
+			c.LegoIDs = append(c.LegoIDs, lc.LegoID)
+			c.LegoNames = append(c.LegoNames, lc.Names...)
+			colorsByLID[lc.LegoID] = c
+			for _, name := range lc.Names {
+				colorsByLN[normalizeName(name)] = c
+			}
+		}
+	})
+}
+
+// normalizeName folds case and drops punctuation and spaces so that LEGO's
+// abbreviations compare equal regardless of spacing, e.g. "DK. ST. GREY"
+// and "DK.ST.GREY".
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// clone returns a copy of c that does not share LegoIDs or LegoNames with
+// the package tables.
+func (c *Color) clone() Color {
+	color := *c
+	color.LegoIDs = append([]int(nil), c.LegoIDs...)
+	color.LegoNames = append([]string(nil), c.LegoNames...)
+	return color
+}
+
+// All returns all colors in the canonical model.
+func All() []Color {
+	index()
+	colors := make([]Color, len(colorList))
+	for i := range colorList {
+		colors[i] = colorList[i].clone()
+	}
+	return colors
+}
+
+// Get returns the color for a canonical ID.
+func Get(id ID) (Color, bool) {
+	index()
+	if c, ok := colorsByID[id]; ok {
+		return c.clone(), true
+	}
+	return Color{}, false
+}
+
+// ByBrickLinkID returns the color for a BrickLink color ID.
+func ByBrickLinkID(colorID int) (Color, bool) {
+	return Get(ID(colorID))
+}
+
+// ByBrickLinkName returns the color for a BrickLink color name, ignoring
+// case and punctuation.
+func ByBrickLinkName(name string) (Color, bool) {
+	index()
+	if c, ok := colorsByBL[normalizeName(name)]; ok {
+		return c.clone(), true
+	}
+	return Color{}, false
+}
+
+// ByLegoID returns the color for a LEGO color ID.
+func ByLegoID(legoID int) (Color, bool) {
+	index()
+	if c, ok := colorsByLID[legoID]; ok {
+		return c.clone(), true
+	}
+	return Color{}, false
+}
+
+// ByLegoName returns the color for a LEGO color name or Bricks & Pieces
+// abbreviation such as "BR.YEL", ignoring case and punctuation.
+func ByLegoName(name string) (Color, bool) {
+	index()
+	if c, ok := colorsByLN[normalizeName(name)]; ok {
+		return c.clone(), true
+	}
+	return Color{}, false
+}
+
+// FromStoreColor converts a color from the BrickLink store API.
+// Colors missing from the catalog tables are built from the API fields.
+func FromStoreColor(c bricklinkstore.Color) Color {
+	if color, ok := ByBrickLinkID(c.ColorID); ok {
+		return color
+	}
+	rgb, _ := ParseRGB(c.ColorCode)
+	return Color{ID: ID(c.ColorID), Name: c.ColorName, RGB: rgb, Group: GroupOf(c.ColorType)}
+}
+
+// FromUserColor converts a color from the BrickLink user API lookups.
+func FromUserColor(c bricklinkuser.Color) Color {
+	if color, ok := ByBrickLinkID(c.ColorID); ok {
+		return color
+	}
+	rgb, _ := ParseRGB(c.RGB)
+	return Color{ID: ID(c.ColorID), Name: c.ColorName, RGB: rgb, Group: Group(c.Group)}
+}
+
+// Nearest returns the color closest to rgb. When groups are given, only
+// colors in those groups are considered.
+func Nearest(rgb RGB, groups ...Group) Color {
+	index()
+	var best *Color
+	bestDistance := math.Inf(1)
+	for i := range colorList {
+		c := &colorList[i]
+		if c.ID == 0 || (len(groups) != 0 && !hasGroup(groups, c.Group)) {
+			continue
+		}
+		if d := rgb.Distance(c.RGB); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	if best == nil {
+		return Color{}
+	}
+	return best.clone()
+}
+
+func hasGroup(groups []Group, g Group) bool {
+	for _, group := range groups {
+		if group == g {
+			return true
+		}
+	}
+	return false
+}
+
+// Match is the result of resolving a LEGO color to a canonical color.
+type Match struct {
+	Color Color
+	Exact bool // Whether the color was found by name rather than approximated
+}
+
+// FromBrick resolves the color of a Bricks & Pieces element. The exact color
+// is found from ColorDescription when it is known, otherwise the nearest
+// solid or transparent color to the broad ColorLikeDescription is used.
+func FromBrick(b legobap.Brick) (Match, bool) {
+	if c, ok := ByLegoName(b.ColorDescription); ok {
+		return Match{c, true}, true
+	}
+	like, ok := colorLikeRGB[normalizeName(b.ColorLikeDescription)]
+	if !ok {
+		return Match{}, false
+	}
+	group := GroupSolid
+	if isTransparent(b.ColorDescription) {
+		group = GroupTransparent
+	}
+	return Match{Nearest(like, group), false}, true
+}
+
+// isTransparent reports whether the first word of a LEGO color description
+// is "TR" or "Transparent", as in "TR. BLUE" or "TR BLUE", rather than only
+// starting with those letters, as in "TRUE BLUE".
+func isTransparent(desc string) bool {
+	desc = strings.ToUpper(strings.TrimSpace(desc))
+	if i := strings.IndexAny(desc, " ."); i >= 0 {
+		desc = desc[:i]
+	}
+	return desc == "TR" || desc == "TRANSPARENT"
+}
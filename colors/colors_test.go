@@ -0,0 +1,99 @@
+package colors
+
+import (
+	"testing"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+	"github.com/andrewarchi/brick-apis/legobap"
+)
+
+func TestLegoColorsMapped(t *testing.T) {
+	for _, lc := range legoColorList {
+		if _, ok := Get(lc.BrickLinkID); !ok {
+			t.Errorf("LEGO color %d %s maps to unknown BrickLink color %d", lc.LegoID, lc.Names[0], lc.BrickLinkID)
+		}
+	}
+}
+
+func TestByLegoName(t *testing.T) {
+	tests := []struct {
+		name string
+		id   ID
+	}{
+		{"BR.YEL", 3},
+		{"DK. ST. GREY", 85},
+		{"dk.st.grey", 85},
+		{"MED. ST-GREY", 86},
+		{"Reddish Brown", 88},
+		{"TR.", 12},
+		{"TR. BR. ORANGE", 98},
+	}
+	for _, test := range tests {
+		c, ok := ByLegoName(test.name)
+		if !ok || c.ID != test.id {
+			t.Errorf("expected %s to be color %d, but got %v", test.name, test.id, c)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	rgb, err := ParseRGB("#FFFFFE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := Nearest(rgb, GroupSolid); c.ID != 1 {
+		t.Errorf("expected White, but got %s", c.Name)
+	}
+	if c := Nearest(RGB{0xB0, 0x00, 0x00}, GroupSolid); c.ID != 5 {
+		t.Errorf("expected Red, but got %s", c.Name)
+	}
+}
+
+func TestFromBrick(t *testing.T) {
+	m, ok := FromBrick(legobap.Brick{ColorDescription: "SAND YELLOW", ColorLikeDescription: "Yellow"})
+	if !ok || !m.Exact || m.Color.ID != 69 {
+		t.Errorf("expected exact Dark Tan, but got %v", m)
+	}
+	m, ok = FromBrick(legobap.Brick{ColorDescription: "GOLD INK", ColorLikeDescription: "Yellow"})
+	if !ok || m.Exact || m.Color.ID != 3 {
+		t.Errorf("expected approximate Yellow, but got %v", m)
+	}
+}
+
+func TestIsTransparent(t *testing.T) {
+	for desc, want := range map[string]bool{
+		"TR. BLUE":         true,
+		"TR.RED":           true,
+		"TR BLUE":          true,
+		"Transparent Blue": true,
+		"TRUE BLUE":        false,
+		"TRAFFIC RED":      false,
+		"":                 false,
+	} {
+		if got := isTransparent(desc); got != want {
+			t.Errorf("isTransparent(%q) = %t, want %t", desc, got, want)
+		}
+	}
+}
+
+func TestGetCopiesSlices(t *testing.T) {
+	c, ok := Get(3)
+	if !ok || len(c.LegoIDs) == 0 || len(c.LegoNames) == 0 {
+		t.Fatalf("expected Yellow with LEGO names, but got %v", c)
+	}
+	c.LegoIDs[0] = -1
+	c.LegoNames[0] = "CORRUPT"
+	if again, _ := Get(3); again.LegoIDs[0] == -1 || again.LegoNames[0] == "CORRUPT" {
+		t.Errorf("modifying a returned color changed the table: %v", again)
+	}
+}
+
+func TestFromStoreColor(t *testing.T) {
+	c := FromStoreColor(bricklinkstore.Color{ColorID: 9999, ColorName: "New", ColorCode: "123456", ColorType: bricklinkstore.ColorTypePearl})
+	if c.RGB.Hex() != "123456" || c.Group != GroupPearl {
+		t.Errorf("unexpected color %v", c)
+	}
+	if c := FromStoreColor(bricklinkstore.Color{ColorID: 11}); c.Name != "Black" {
+		t.Errorf("expected Black, but got %v", c)
+	}
+}
@@ -0,0 +1,80 @@
+package colors
+
+type legoColor struct {
+	LegoID      int
+	BrickLinkID ID
+	Names       []string // Official name followed by abbreviations seen in Bricks & Pieces
+}
+
+// LEGO color IDs and names mapped to BrickLink colors.
+var legoColorList = []legoColor{
+	{1, 1, []string{"White", "WHITE"}},
+	{5, 2, []string{"Brick Yellow", "BRICK-YEL"}},
+	{18, 28, []string{"Nougat", "NOUGAT"}},
+	{21, 5, []string{"Bright Red", "BR.RED"}},
+	{23, 7, []string{"Bright Blue", "BR.BLUE"}},
+	{24, 3, []string{"Bright Yellow", "BR.YEL"}},
+	{26, 11, []string{"Black", "BLACK"}},
+	{28, 6, []string{"Dark Green", "DK.GREEN"}},
+	{37, 36, []string{"Bright Green", "BR.GREEN"}},
+	{38, 68, []string{"Dark Orange", "DK.ORA"}},
+	{40, 12, []string{"Transparent", "TR."}},
+	{41, 17, []string{"Transparent Red", "TR.RED"}},
+	{42, 15, []string{"Transparent Light Blue", "TR.L.BLUE"}},
+	{43, 14, []string{"Transparent Blue", "TR.BLUE"}},
+	{44, 19, []string{"Transparent Yellow", "TR.YEL"}},
+	{48, 20, []string{"Transparent Green", "TR.GREEN"}},
+	{49, 16, []string{"Transparent Fluorescent Green", "TR.FL.GREEN"}},
+	{102, 42, []string{"Medium Blue", "MD.BLUE"}},
+	{106, 4, []string{"Bright Orange", "BR.ORANGE"}},
+	{107, 39, []string{"Bright Bluish Green", "BR.BLUEGREEN"}},
+	{111, 13, []string{"Transparent Brown", "TR.BROWN"}},
+	{119, 34, []string{"Bright Yellowish Green", "BR.YEL-GREEN"}},
+	{124, 71, []string{"Bright Reddish Violet", "BR.RED-VIOL"}},
+	{135, 55, []string{"Sand Blue", "SAND BLUE"}},
+	{138, 69, []string{"Sand Yellow", "SAND YELLOW"}},
+	{140, 63, []string{"Earth Blue", "EARTH BLUE"}},
+	{148, 77, []string{"Metallic Dark Grey", "MET.DK.GREY"}},
+	{151, 48, []string{"Sand Green", "SAND GREEN"}},
+	{154, 59, []string{"New Dark Red", "NEW DARK RED"}},
+	{182, 98, []string{"Transparent Bright Orange", "TR. BR. ORANGE"}},
+	{191, 110, []string{"Flame Yellowish Orange", "FL. YELL-ORA"}},
+	{192, 88, []string{"Reddish Brown", "RED. BROWN"}},
+	{194, 86, []string{"Medium Stone Grey", "MED. ST-GREY"}},
+	{199, 85, []string{"Dark Stone Grey", "DK. ST. GREY"}},
+	{212, 105, []string{"Light Royal Blue", "L.ROY.BLUE"}},
+	{221, 47, []string{"Bright Purple", "BR.PURPLE"}},
+	{222, 104, []string{"Light Purple", "LGH. PURPLE"}},
+	{226, 103, []string{"Cool Yellow", "COOL YELLOW"}},
+	{268, 89, []string{"Medium Lilac", "M. LILAC"}},
+	{297, 115, []string{"Warm Gold", "W.GOLD"}},
+	{308, 120, []string{"Dark Brown", "DK. BROWN"}},
+	{312, 150, []string{"Medium Nougat", "M. NOUGAT"}},
+	{315, 95, []string{"Silver Metallic", "SILVER MET."}},
+	{321, 153, []string{"Dark Azur", "DARK AZUR"}},
+	{322, 156, []string{"Medium Azur", "MEDIUM AZUR"}},
+	{323, 152, []string{"Aqua", "AQUA"}},
+	{324, 157, []string{"Medium Lavender", "MEDIUM LAV"}},
+	{325, 154, []string{"Lavender", "LAVENDER"}},
+	{326, 158, []string{"Spring Yellowish Green", "SPR. YEL. GREEN"}},
+	{330, 155, []string{"Olive Green", "OLIVE GREEN"}},
+	{353, 220, []string{"Vibrant Coral", "VIB. CORAL"}},
+}
+
+// Representative values for the broad colors in legobap.Brick.ColorLikeDescription.
+var colorLikeRGB = map[string]RGB{
+	"BLACK":       {0x21, 0x21, 0x21},
+	"BLUE":        {0x00, 0x57, 0xA6},
+	"BROWN":       {0x89, 0x35, 0x1D},
+	"GREEN":       {0x00, 0x64, 0x2E},
+	"GREY":        {0xAF, 0xB5, 0xC7},
+	"GRAY":        {0xAF, 0xB5, 0xC7},
+	"ORANGE":      {0xFF, 0x7E, 0x14},
+	"PINK":        {0xFF, 0xBB, 0xFF},
+	"PURPLE":      {0x5F, 0x26, 0x83},
+	"RED":         {0xB3, 0x00, 0x06},
+	"WHITE":       {0xFF, 0xFF, 0xFF},
+	"YELLOW":      {0xF7, 0xD1, 0x17},
+	"BEIGE":       {0xDE, 0xC6, 0x9C},
+	"TRANSPARENT": {0xEE, 0xEE, 0xEE},
+}
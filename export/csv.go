@@ -0,0 +1,60 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{
+	"order_id", "date", "date_ordered", "date_paid", "buyer", "status", "currency",
+	"subtotal", "shipping", "insurance", "etc1", "etc2", "sales_tax",
+	"vat_rate", "vat_amount", "credit", "coupon", "grand_total",
+}
+
+// WriteCSV writes a row per order with the amounts in each column,
+// followed by a total row per currency.
+func WriteCSV(w io.Writer, l *Ledger) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range l.Entries {
+		if err := cw.Write(csvRow(e)); err != nil {
+			return err
+		}
+	}
+	for _, currency := range l.Currencies() {
+		row := csvRow(l.Total(currency))
+		row[0] = "total"
+		row[13] = ""
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvRow(e Entry) []string {
+	orderID := ""
+	if e.OrderID != 0 {
+		orderID = strconv.Itoa(e.OrderID)
+	}
+	return []string{
+		orderID, csvDate(e.Date), csvDate(e.DateOrdered), csvDate(e.DatePaid),
+		e.Buyer, string(e.Status), string(e.Currency),
+		e.Subtotal.String(), e.Shipping.String(), e.Insurance.String(),
+		e.Etc1.String(), e.Etc2.String(), e.SalesTax.String(),
+		e.VATRate.String(), e.VATAmount.String(), e.Credit.String(),
+		e.Coupon.String(), e.GrandTotal.String(),
+	}
+}
+
+func csvDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,208 @@
+// Package export produces sales ledgers of a BrickLink store for
+// bookkeeping and tax filing.
+//
+// A Ledger holds the cost breakdown of each order received in a date range
+// and can be written as CSV, OFX or plain-text ledger entries. Amounts are
+// exact to BrickLink's 4 decimal places and kept in the currency of the
+// store.
+package export
+
+import (
+	"sort"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+// DateField selects the date that orders are filtered and dated by.
+type DateField int
+
+// Dates of an order.
+const (
+	DateOrdered DateField = iota // Order.DateOrdered
+	DatePaid                     // Order.Payment.DatePaid
+)
+
+// Filter selects the orders in a ledger.
+type Filter struct {
+	From, To        time.Time // Range [From, To); zero leaves that end unbounded
+	DateField       DateField
+	IncludeStatuses []string // Order statuses to include, or all when empty
+	ExcludeStatuses []string // Order statuses to exclude, such as "CANCELLED"
+	Items           bool     // Whether to fetch the items of each order
+}
+
+func (f *Filter) date(o *bricklinkstore.Order) time.Time {
+	if f.DateField == DatePaid {
+		return o.Payment.DatePaid
+	}
+	return o.DateOrdered
+}
+
+// contains reports whether t is in the range. Unpaid orders have a zero
+// DatePaid and are never in a range by date paid.
+func (f *Filter) contains(t time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	return (f.From.IsZero() || !t.Before(f.From)) && (f.To.IsZero() || t.Before(f.To))
+}
+
+// Entry is the cost breakdown of an order.
+type Entry struct {
+	OrderID     int
+	Date        time.Time // Date selected by the filter
+	DateOrdered time.Time
+	DatePaid    time.Time
+	Buyer       string
+	Status      bricklinkstore.OrderStatus
+	Currency    money.Currency
+	Subtotal    bricklinkstore.Amount
+	Shipping    bricklinkstore.Amount
+	Insurance   bricklinkstore.Amount
+	Etc1        bricklinkstore.Amount
+	Etc2        bricklinkstore.Amount
+	SalesTax    bricklinkstore.Amount
+	VATRate     bricklinkstore.Amount // Percentage
+	VATAmount   bricklinkstore.Amount // Included in GrandTotal
+	Credit      bricklinkstore.Amount
+	Coupon      bricklinkstore.Amount
+	GrandTotal  bricklinkstore.Amount
+	Items       []bricklinkstore.OrderItem // Only when fetched with Filter.Items
+}
+
+// NewEntry returns the entry of an order dated by a field.
+func NewEntry(o *bricklinkstore.Order, field DateField) Entry {
+	f := Filter{DateField: field}
+	c := o.Cost
+	return Entry{
+		OrderID:     o.OrderID,
+		Date:        f.date(o),
+		DateOrdered: o.DateOrdered,
+		DatePaid:    o.Payment.DatePaid,
+		Buyer:       o.BuyerName,
+		Status:      o.Status,
+		Currency:    money.Currency(c.CurrencyCode),
+		Subtotal:    c.Subtotal,
+		Shipping:    c.Shipping,
+		Insurance:   c.Insurance,
+		Etc1:        c.Etc1,
+		Etc2:        c.Etc2,
+		SalesTax:    c.SalesTax,
+		VATRate:     c.VATRate,
+		VATAmount:   c.VATAmount,
+		Credit:      c.Credit,
+		Coupon:      c.Coupon,
+		GrandTotal:  c.GrandTotal,
+	}
+}
+
+// ItemsTotal returns the sum of the final prices of the items, which
+// should equal Subtotal.
+func (e *Entry) ItemsTotal() bricklinkstore.Amount {
+	var total bricklinkstore.Amount
+	for _, item := range e.Items {
+		total += item.UnitPriceFinal.MulInt(item.Quantity)
+	}
+	return total
+}
+
+// Unreconciled returns the part of the grand total that is not explained
+// by the other costs. It is normally zero.
+func (e *Entry) Unreconciled() bricklinkstore.Amount {
+	return e.GrandTotal - (e.Subtotal + e.Shipping + e.Insurance + e.Etc1 + e.Etc2 + e.SalesTax - e.Credit - e.Coupon)
+}
+
+// Ledger is the entries of the orders in a date range, in order of date.
+type Ledger struct {
+	From, To time.Time
+	Entries  []Entry
+}
+
+// NewLedger builds a ledger from orders, keeping those in the range of the
+// filter. Statuses in the filter are not checked, since the API filters
+// them.
+func NewLedger(orders []bricklinkstore.Order, filter Filter) *Ledger {
+	l := &Ledger{From: filter.From, To: filter.To}
+	for i := range orders {
+		if filter.contains(filter.date(&orders[i])) {
+			l.Entries = append(l.Entries, NewEntry(&orders[i], filter.DateField))
+		}
+	}
+	sort.SliceStable(l.Entries, func(i, j int) bool {
+		return l.Entries[i].Date.Before(l.Entries[j].Date)
+	})
+	return l
+}
+
+// Fetch builds a ledger of the orders received by the store, both filed
+// and unfiled.
+func Fetch(c *bricklinkstore.Client, filter Filter) (*Ledger, error) {
+	var orders []bricklinkstore.Order
+	for _, filed := range []bool{false, true} {
+		list, err := c.GetOrdersByStatus("in", filter.IncludeStatuses, filter.ExcludeStatuses, filed)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range list {
+			// The payment date is only known from the order details.
+			if filter.DateField == DateOrdered && !filter.contains(o.DateOrdered) {
+				continue
+			}
+			order, err := c.GetOrder(o.OrderID)
+			if err != nil {
+				return nil, err
+			}
+			orders = append(orders, *order)
+		}
+	}
+	l := NewLedger(orders, filter)
+	if filter.Items {
+		for i := range l.Entries {
+			batches, err := c.GetOrderItems(l.Entries[i].OrderID)
+			if err != nil {
+				return nil, err
+			}
+			for _, batch := range batches {
+				l.Entries[i].Items = append(l.Entries[i].Items, batch...)
+			}
+		}
+	}
+	return l, nil
+}
+
+// Currencies returns the currencies of the entries in sorted order.
+func (l *Ledger) Currencies() []money.Currency {
+	seen := make(map[money.Currency]bool)
+	var currencies []money.Currency
+	for _, e := range l.Entries {
+		if !seen[e.Currency] {
+			seen[e.Currency] = true
+			currencies = append(currencies, e.Currency)
+		}
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+	return currencies
+}
+
+// Total sums the entries in a currency. The total has no order or dates.
+func (l *Ledger) Total(currency money.Currency) Entry {
+	t := Entry{Currency: currency}
+	for _, e := range l.Entries {
+		if e.Currency != currency {
+			continue
+		}
+		t.Subtotal += e.Subtotal
+		t.Shipping += e.Shipping
+		t.Insurance += e.Insurance
+		t.Etc1 += e.Etc1
+		t.Etc2 += e.Etc2
+		t.SalesTax += e.SalesTax
+		t.VATAmount += e.VATAmount
+		t.Credit += e.Credit
+		t.Coupon += e.Coupon
+		t.GrandTotal += e.GrandTotal
+	}
+	return t
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+)
+
+func amount(s string) bricklinkstore.Amount {
+	a, err := bricklinkstore.ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func testOrders() []bricklinkstore.Order {
+	day := func(m, d int) time.Time { return time.Date(2020, time.Month(m), d, 12, 0, 0, 0, time.UTC) }
+	return []bricklinkstore.Order{
+		{
+			OrderID: 2, DateOrdered: day(2, 1), BuyerName: "bob", Status: bricklinkstore.OrderCompleted,
+			Payment: bricklinkstore.Payment{DatePaid: day(2, 2)},
+			Cost: bricklinkstore.Cost{
+				CurrencyCode: "EUR", Subtotal: amount("12.10"), Shipping: amount("3.00"),
+				VATRate: amount("21"), VATAmount: amount("2.10"), Coupon: amount("1.00"), GrandTotal: amount("14.10"),
+			},
+		},
+		{
+			OrderID: 1, DateOrdered: day(1, 31), BuyerName: "alice", Status: bricklinkstore.OrderCompleted,
+			Payment: bricklinkstore.Payment{DatePaid: day(2, 3)},
+			Cost: bricklinkstore.Cost{
+				CurrencyCode: "USD", Subtotal: amount("10.00"), Shipping: amount("2.50"), Insurance: amount("0.50"),
+				Etc1: amount("0.25"), SalesTax: amount("0.80"), Credit: amount("1.00"), GrandTotal: amount("13.05"),
+			},
+		},
+		{
+			OrderID: 3, DateOrdered: day(3, 1), BuyerName: "carol", Status: bricklinkstore.OrderPending,
+			Cost: bricklinkstore.Cost{CurrencyCode: "USD", Subtotal: amount("5.00"), GrandTotal: amount("5.00")},
+		},
+	}
+}
+
+func TestNewLedger(t *testing.T) {
+	feb := Filter{From: time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewLedger(testOrders(), feb)
+	if len(l.Entries) != 1 || l.Entries[0].OrderID != 2 {
+		t.Errorf("by date ordered: got %+v", l.Entries)
+	}
+	feb.DateField = DatePaid
+	l = NewLedger(testOrders(), feb)
+	if len(l.Entries) != 2 || l.Entries[0].OrderID != 2 || l.Entries[1].OrderID != 1 {
+		t.Errorf("by date paid: got %+v", l.Entries)
+	}
+	for _, e := range l.Entries {
+		if u := e.Unreconciled(); u != 0 {
+			t.Errorf("order %d: unreconciled %s", e.OrderID, u)
+		}
+	}
+	if got := l.Currencies(); len(got) != 2 || got[0] != "EUR" || got[1] != "USD" {
+		t.Errorf("got currencies %v", got)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	l := NewLedger(testOrders(), Filter{DateField: DatePaid})
+	var b bytes.Buffer
+	if err := WriteCSV(&b, l); err != nil {
+		t.Fatal(err)
+	}
+	want := `order_id,date,date_ordered,date_paid,buyer,status,currency,subtotal,shipping,insurance,etc1,etc2,sales_tax,vat_rate,vat_amount,credit,coupon,grand_total
+2,2020-02-02,2020-02-01,2020-02-02,bob,COMPLETED,EUR,12.10,3.00,0.00,0.00,0.00,0.00,21.00,2.10,0.00,1.00,14.10
+1,2020-02-03,2020-01-31,2020-02-03,alice,COMPLETED,USD,10.00,2.50,0.50,0.25,0.00,0.80,0.00,0.00,1.00,0.00,13.05
+total,,,,,,EUR,12.10,3.00,0.00,0.00,0.00,0.00,,2.10,0.00,1.00,14.10
+total,,,,,,USD,10.00,2.50,0.50,0.25,0.00,0.80,,0.00,1.00,0.00,13.05
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteLedger(t *testing.T) {
+	l := NewLedger(testOrders(), Filter{})
+	var b bytes.Buffer
+	if err := WriteLedger(&b, l, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `2020/01/31 * (1) BrickLink order from alice
+    Assets:BrickLink                          USD 13.05
+    Income:BrickLink:Sales                    USD -10.00
+    Income:BrickLink:Shipping                 USD -2.50
+    Income:BrickLink:Insurance                USD -0.50
+    Income:BrickLink:Other                    USD -0.25
+    Liabilities:SalesTax                      USD -0.80
+    Expenses:BrickLink:Credits                USD 1.00
+
+2020/02/01 * (2) BrickLink order from bob
+    ; VAT rate: 21.00%
+    Assets:BrickLink                          EUR 14.10
+    Income:BrickLink:Sales                    EUR -10.00
+    Income:BrickLink:Shipping                 EUR -3.00
+    Liabilities:VAT                           EUR -2.10
+    Expenses:BrickLink:Coupons                EUR 1.00
+
+2020/03/01 ! (3) BrickLink order from carol
+    Assets:BrickLink                          USD 5.00
+    Income:BrickLink:Sales                    USD -5.00
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestWriteOFX(t *testing.T) {
+	l := NewLedger(testOrders(), Filter{})
+	var b bytes.Buffer
+	if err := WriteOFX(&b, l, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	var amounts, currencies, trnuids []string
+	decoder := xml.NewDecoder(&b)
+	var element string
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			element = tok.Name.Local
+		case xml.CharData:
+			switch element {
+			case "TRNAMT":
+				amounts = append(amounts, string(tok))
+			case "CURDEF":
+				currencies = append(currencies, string(tok))
+			case "TRNUID":
+				trnuids = append(trnuids, string(tok))
+			}
+			element = ""
+		}
+	}
+	if strings.Join(currencies, ",") != "EUR,USD" {
+		t.Errorf("got currencies %v", currencies)
+	}
+	if strings.Join(amounts, ",") != "14.10,13.05,5.00" {
+		t.Errorf("got amounts %v", amounts)
+	}
+	if strings.Join(trnuids, ",") != "STORE-EUR,STORE-USD" {
+		t.Errorf("got transaction IDs %v", trnuids)
+	}
+	if !strings.Contains(out, "<SONRS>") || !strings.Contains(out, "<DTSERVER>20200301120000</DTSERVER>") {
+		t.Errorf("missing or misdated signon response:\n%s", out)
+	}
+
+	if err := WriteOFX(&b, &Ledger{Entries: []Entry{{Currency: "USD"}}}, nil); err == nil {
+		t.Error("expected error for undated ledger")
+	}
+}
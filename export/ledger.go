@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+)
+
+// LedgerAccounts are the accounts posted to by WriteLedger.
+type LedgerAccounts struct {
+	Receivable   string // Debited with the grand total
+	Sales        string // Credited with the subtotal less VAT
+	Shipping     string
+	Insurance    string
+	Other        string // Etc1 and Etc2
+	SalesTax     string
+	VAT          string
+	Credits      string // Debited with store credit applied
+	Coupons      string // Debited with coupon discounts
+	Unreconciled string // Any remainder of the grand total
+}
+
+// DefaultLedgerAccounts is used by WriteLedger when no accounts are given.
+var DefaultLedgerAccounts = LedgerAccounts{
+	Receivable:   "Assets:BrickLink",
+	Sales:        "Income:BrickLink:Sales",
+	Shipping:     "Income:BrickLink:Shipping",
+	Insurance:    "Income:BrickLink:Insurance",
+	Other:        "Income:BrickLink:Other",
+	SalesTax:     "Liabilities:SalesTax",
+	VAT:          "Liabilities:VAT",
+	Credits:      "Expenses:BrickLink:Credits",
+	Coupons:      "Expenses:BrickLink:Coupons",
+	Unreconciled: "Income:BrickLink:Unreconciled",
+}
+
+// WriteLedger writes a balanced transaction per order in the plain-text
+// format of Ledger and hledger. VAT, which is included in the prices, is
+// moved from sales to its liability account.
+func WriteLedger(w io.Writer, l *Ledger, accounts *LedgerAccounts) error {
+	if accounts == nil {
+		accounts = &DefaultLedgerAccounts
+	}
+	for i, e := range l.Entries {
+		if i != 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		status := "!"
+		if !e.DatePaid.IsZero() {
+			status = "*"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s (%d) BrickLink order from %s\n", e.Date.UTC().Format("2006/01/02"), status, e.OrderID, e.Buyer); err != nil {
+			return err
+		}
+		if e.VATAmount != 0 {
+			if _, err := fmt.Fprintf(w, "    ; VAT rate: %s%%\n", e.VATRate); err != nil {
+				return err
+			}
+		}
+		postings := []struct {
+			account string
+			amount  bricklinkstore.Amount
+		}{
+			{accounts.Receivable, e.GrandTotal},
+			{accounts.Sales, -(e.Subtotal - e.VATAmount)},
+			{accounts.Shipping, -e.Shipping},
+			{accounts.Insurance, -e.Insurance},
+			{accounts.Other, -(e.Etc1 + e.Etc2)},
+			{accounts.SalesTax, -e.SalesTax},
+			{accounts.VAT, -e.VATAmount},
+			{accounts.Credits, e.Credit},
+			{accounts.Coupons, e.Coupon},
+			{accounts.Unreconciled, -e.Unreconciled()},
+		}
+		for j, p := range postings {
+			if p.amount == 0 && j != 0 {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "    %-40s  %s %s\n", p.account, e.Currency, p.amount); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+// OFXAccount identifies the account in an OFX statement.
+type OFXAccount struct {
+	BankID    string
+	AccountID string
+}
+
+// DefaultOFXAccount is used by WriteOFX when no account is given.
+var DefaultOFXAccount = OFXAccount{BankID: "BRICKLINK", AccountID: "STORE"}
+
+// WriteOFX writes an OFX 2.2 bank statement per currency with a credit
+// per order for its grand total. The breakdown of each order is given in
+// the memo. Dates are taken from the ledger range and the entries, so the
+// ledger must have at least one.
+func WriteOFX(w io.Writer, l *Ledger, account *OFXAccount) error {
+	if account == nil {
+		account = &DefaultOFXAccount
+	}
+	from, asOf := ofxRange(l, "")
+	if asOf.IsZero() {
+		return fmt.Errorf("export: ledger has no dates for OFX")
+	}
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n<SIGNONMSGSRSV1>\n<SONRS>\n<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	ofxElement(&b, "DTSERVER", ofxDate(asOf))
+	ofxElement(&b, "LANGUAGE", "ENG")
+	b.WriteString("</SONRS>\n</SIGNONMSGSRSV1>\n<BANKMSGSRSV1>\n")
+	for _, currency := range l.Currencies() {
+		start, end := ofxRange(l, currency)
+		if end.IsZero() {
+			start, end = from, asOf
+		}
+		total := l.Total(currency).GrandTotal
+		b.WriteString("<STMTTRNRS>\n")
+		ofxElement(&b, "TRNUID", account.AccountID+"-"+string(currency))
+		b.WriteString("<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n<STMTRS>\n")
+		ofxElement(&b, "CURDEF", string(currency))
+		b.WriteString("<BANKACCTFROM>\n")
+		ofxElement(&b, "BANKID", account.BankID)
+		ofxElement(&b, "ACCTID", account.AccountID+"-"+string(currency))
+		ofxElement(&b, "ACCTTYPE", "CHECKING")
+		b.WriteString("</BANKACCTFROM>\n<BANKTRANLIST>\n")
+		ofxElement(&b, "DTSTART", ofxDate(start))
+		ofxElement(&b, "DTEND", ofxDate(end))
+		for _, e := range l.Entries {
+			if e.Currency != currency {
+				continue
+			}
+			posted := e.Date
+			if posted.IsZero() {
+				posted = end
+			}
+			b.WriteString("<STMTTRN>\n")
+			ofxElement(&b, "TRNTYPE", "CREDIT")
+			ofxElement(&b, "DTPOSTED", ofxDate(posted))
+			ofxElement(&b, "TRNAMT", e.GrandTotal.StringFixed(2))
+			ofxElement(&b, "FITID", fmt.Sprintf("BL%d", e.OrderID))
+			ofxElement(&b, "NAME", truncate(fmt.Sprintf("BrickLink order %d %s", e.OrderID, e.Buyer), 32))
+			ofxElement(&b, "MEMO", ofxMemo(e))
+			b.WriteString("</STMTTRN>\n")
+		}
+		b.WriteString("</BANKTRANLIST>\n<LEDGERBAL>\n")
+		ofxElement(&b, "BALAMT", total.StringFixed(2))
+		ofxElement(&b, "DTASOF", ofxDate(end))
+		b.WriteString("</LEDGERBAL>\n</STMTRS>\n</STMTTRNRS>\n")
+	}
+	b.WriteString("</BANKMSGSRSV1>\n</OFX>\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// ofxRange returns the range of a statement, which is the ledger range
+// widened to the dates of its entries in a currency, or in all currencies
+// when currency is empty. Either end may be zero when nothing is dated.
+func ofxRange(l *Ledger, currency money.Currency) (start, end time.Time) {
+	start, end = l.From, l.To
+	for _, e := range l.Entries {
+		if (currency != "" && e.Currency != currency) || e.Date.IsZero() {
+			continue
+		}
+		if start.IsZero() || e.Date.Before(start) {
+			start = e.Date
+		}
+		if end.IsZero() || e.Date.After(end) {
+			end = e.Date
+		}
+	}
+	if start.IsZero() {
+		start = end
+	}
+	if end.IsZero() {
+		end = start
+	}
+	return start, end
+}
+
+func ofxElement(b *bytes.Buffer, name, value string) {
+	b.WriteString("<" + name + ">")
+	xml.EscapeText(b, []byte(value))
+	b.WriteString("</" + name + ">\n")
+}
+
+func ofxDate(t time.Time) string {
+	return t.UTC().Format("20060102150405")
+}
+
+// ofxMemo lists the subtotal and nonzero costs of an order.
+func ofxMemo(e Entry) string {
+	memo := []string{"Subtotal " + e.Subtotal.StringFixed(2)}
+	costs := []struct {
+		name   string
+		amount bricklinkstore.Amount
+	}{
+		{"Shipping", e.Shipping},
+		{"Insurance", e.Insurance},
+		{"Etc1", e.Etc1},
+		{"Etc2", e.Etc2},
+		{"Sales tax", e.SalesTax},
+		{"VAT", e.VATAmount},
+		{"Credit", e.Credit},
+		{"Coupon", e.Coupon},
+	}
+	for _, c := range costs {
+		if c.amount != 0 {
+			memo = append(memo, c.name+" "+c.amount.StringFixed(2))
+		}
+	}
+	return truncate(strings.Join(memo, ", "), 255)
+}
+
+// truncate shortens s to at most n runes, the unit of OFX field limits.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,62 @@
+// Package pool runs requests with a bounded number of workers and spaces
+// out the starts of requests to the same host.
+package pool
+
+import (
+	"sync"
+	"time"
+)
+
+// Run calls do for each index from 0 to n-1 with at most workers calls in
+// parallel and returns once all calls have finished.
+func Run(n, workers int, do func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				do(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// Throttle spaces out requests to the same host. A nil Throttle does not
+// wait.
+type Throttle struct {
+	mu    sync.Mutex
+	delay time.Duration
+	next  map[string]time.Time
+}
+
+// NewThrottle creates a throttle with a minimum delay between the starts
+// of requests to a host.
+func NewThrottle(delay time.Duration) *Throttle {
+	return &Throttle{delay: delay, next: make(map[string]time.Time)}
+}
+
+// Wait blocks until a request to host may start.
+func (t *Throttle) Wait(host string) {
+	if t == nil || t.delay <= 0 {
+		return
+	}
+	t.mu.Lock()
+	now := time.Now()
+	at := t.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	t.next[host] = at.Add(t.delay)
+	t.mu.Unlock()
+	time.Sleep(time.Until(at))
+}
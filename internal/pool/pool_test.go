@@ -0,0 +1,50 @@
+package pool
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	done := make([]bool, 10)
+	Run(len(done), 3, func(i int) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		done[i] = true
+		mu.Unlock()
+	})
+	for i, ok := range done {
+		if !ok {
+			t.Errorf("job %d not run", i)
+		}
+	}
+	if maxRunning > 3 {
+		t.Errorf("got %d jobs in parallel, want at most 3", maxRunning)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	th := NewThrottle(20 * time.Millisecond)
+	start := time.Now()
+	th.Wait("a")
+	th.Wait("b") // Other hosts are not delayed
+	if d := time.Since(start); d >= 20*time.Millisecond {
+		t.Errorf("first requests to two hosts took %v", d)
+	}
+	th.Wait("a")
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("second request to a host started after %v", d)
+	}
+	var nilThrottle *Throttle
+	nilThrottle.Wait("a")
+}
@@ -0,0 +1,149 @@
+// Package itemref identifies catalog items across BrickLink, Brickset and
+// LEGO Bricks & Pieces.
+//
+// Items are identified by their BrickLink type and number and, for parts,
+// their BrickLink color. LEGO element and design IDs are carried along when
+// known, since they are how LEGO refers to parts.
+package itemref
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+	"github.com/andrewarchi/brick-apis/bricklinkuser"
+	"github.com/andrewarchi/brick-apis/brickset"
+	"github.com/andrewarchi/brick-apis/colors"
+	"github.com/andrewarchi/brick-apis/legobap"
+)
+
+// ItemRef identifies an item independently of the service it came from.
+type ItemRef struct {
+	Type      Type   // BrickLink item type
+	No        string // BrickLink item number, with a variant for sets such as "75192-1"
+	ColorID   int    // BrickLink color ID, or 0 when the item has no color
+	ElementID int    // LEGO element ID, or 0 when unknown
+	DesignID  int    // LEGO design ID, or 0 when unknown
+}
+
+// New creates a reference, normalizing the item number for its type.
+func New(t Type, no string, colorID int) ItemRef {
+	return ItemRef{Type: t, No: NormalizeNo(t, no), ColorID: colorID}
+}
+
+// Key identifies the item by type, number and color, ignoring the LEGO IDs
+// which not every service provides. References from different services to
+// the same item have the same key.
+func (r ItemRef) Key() string {
+	if r.ColorID == 0 {
+		return string(r.Type) + ":" + r.No
+	}
+	return string(r.Type) + ":" + r.No + ":" + strconv.Itoa(r.ColorID)
+}
+
+// Same reports whether two references identify the same item.
+func (r ItemRef) Same(o ItemRef) bool {
+	return r.Key() == o.Key()
+}
+
+func (r ItemRef) String() string {
+	s := r.Type.Name() + " " + r.No
+	if r.ColorID != 0 {
+		if c, ok := colors.ByBrickLinkID(r.ColorID); ok {
+			return s + " in " + c.Name
+		}
+		return fmt.Sprintf("%s in color %d", s, r.ColorID)
+	}
+	return s
+}
+
+// FromCatalogItem converts an item from the BrickLink store API catalog.
+func FromCatalogItem(item bricklinkstore.CatalogItem, colorID int) ItemRef {
+	return New(FromStoreType(item.Type), item.No, colorID)
+}
+
+// FromOrderItem converts an item in a BrickLink store API order.
+func FromOrderItem(item bricklinkstore.OrderItem) ItemRef {
+	return FromCatalogItem(item.Item, item.ColorID)
+}
+
+// FromWantedItem converts an item in a BrickLink wanted list.
+func FromWantedItem(item bricklinkuser.WantedItem) ItemRef {
+	return New(FromUserType(item.ItemType), item.ItemNumber, item.ColorID)
+}
+
+// FromBricksetSet converts a set from version 2 of the Brickset API.
+func FromBricksetSet(set brickset.GetSetResponseItem) ItemRef {
+	return ItemRef{Type: Set, No: JoinSetNumber(set.Number, set.NumberVariant)}
+}
+
+// FromBricksetSetV3 converts a set from version 3 of the Brickset API.
+func FromBricksetSetV3(set brickset.Set) ItemRef {
+	return ItemRef{Type: Set, No: JoinSetNumber(set.Number, set.NumberVariant)}
+}
+
+// FromBrick converts an element from LEGO Bricks & Pieces. The BrickLink
+// part number is assumed to be the design ID, which holds for most parts,
+// and the color is resolved from the LEGO color name. exact is false when
+// the color could not be resolved exactly. Elements without a design ID
+// cannot be converted.
+func FromBrick(b legobap.Brick) (ref ItemRef, exact bool, err error) {
+	if b.DesignID <= 0 {
+		return ItemRef{}, false, fmt.Errorf("itemref: element %d has no design ID", b.ItemNo)
+	}
+	ref = ItemRef{Type: Part, No: strconv.Itoa(b.DesignID), ElementID: b.ItemNo, DesignID: b.DesignID}
+	m, found := colors.FromBrick(b)
+	if found {
+		ref.ColorID = int(m.Color.ID)
+	}
+	return ref, found && m.Exact, nil
+}
+
+// StoreItem returns the item in the form used by the BrickLink store API.
+func (r ItemRef) StoreItem() (itemType bricklinkstore.ItemType, no string, colorID int) {
+	return r.Type.StoreType(), r.No, r.ColorID
+}
+
+// NormalizeNo normalizes an item number for its type. Set, instruction and
+// original box numbers are given a variant when missing, others are only
+// trimmed.
+func NormalizeNo(t Type, no string) string {
+	no = strings.TrimSpace(no)
+	switch t {
+	case Set, Instruction, OriginalBox:
+		return NormalizeSetNumber(no)
+	}
+	return no
+}
+
+// NormalizeSetNumber adds the default variant to a set number without one,
+// so that "75192" and "75192-" become "75192-1".
+func NormalizeSetNumber(no string) string {
+	number, variant := SplitSetNumber(no)
+	if number == "" {
+		return ""
+	}
+	return JoinSetNumber(number, variant)
+}
+
+// SplitSetNumber splits a set number into its number and variant. The
+// variant is 1 when missing. Trailing dashes are dropped.
+func SplitSetNumber(no string) (number string, variant int) {
+	no = strings.TrimRight(strings.TrimSpace(no), "-")
+	if i := strings.LastIndexByte(no, '-'); i >= 0 {
+		if v, err := strconv.Atoi(no[i+1:]); err == nil && v > 0 {
+			return no[:i], v
+		}
+	}
+	return no, 1
+}
+
+// JoinSetNumber joins a set number and variant as in "75192-1". A variant
+// of 0 is treated as 1.
+func JoinSetNumber(number string, variant int) string {
+	if variant <= 0 {
+		variant = 1
+	}
+	return number + "-" + strconv.Itoa(variant)
+}
@@ -0,0 +1,76 @@
+package itemref
+
+import (
+	"testing"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+	"github.com/andrewarchi/brick-apis/bricklinkuser"
+	"github.com/andrewarchi/brick-apis/brickset"
+	"github.com/andrewarchi/brick-apis/legobap"
+)
+
+func TestSetNumbers(t *testing.T) {
+	tests := []struct {
+		no, want string
+	}{
+		{"75192", "75192-1"},
+		{"75192-1", "75192-1"},
+		{" 6399-2 ", "6399-2"},
+		{"bb1234-", "bb1234-1"},
+		{"75192-2-", "75192-2"},
+		{"", ""},
+		{"-", ""},
+	}
+	for _, test := range tests {
+		if got := NormalizeSetNumber(test.no); got != test.want {
+			t.Errorf("NormalizeSetNumber(%q) = %q, want %q", test.no, got, test.want)
+		}
+	}
+	if number, variant := SplitSetNumber("10179-1"); number != "10179" || variant != 1 {
+		t.Errorf("unexpected split %s, %d", number, variant)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	for _, s := range []string{"P", "p", "PART", "Part", "parts"} {
+		if got := ParseType(s); got != Part {
+			t.Errorf("ParseType(%q) = %q, want %q", s, got, Part)
+		}
+	}
+	if ParseType("brick") != "" {
+		t.Error("expected unknown type")
+	}
+	if Minifig.StoreType() != bricklinkstore.ItemTypeMinifig || FromStoreType(bricklinkstore.ItemTypeOriginalBox) != OriginalBox {
+		t.Error("unexpected store type mapping")
+	}
+	if OriginalBox.Name() != "Original Box" || Gear.PluralName() != "Gear" {
+		t.Errorf("unexpected names %q, %q", OriginalBox.Name(), Gear.PluralName())
+	}
+}
+
+func TestConverters(t *testing.T) {
+	store := FromCatalogItem(bricklinkstore.CatalogItem{No: "75192", Type: bricklinkstore.ItemTypeSet}, 0)
+	wanted := FromWantedItem(bricklinkuser.WantedItem{ItemNumber: "75192-1", ItemType: bricklinkuser.ItemTypeSet})
+	set := FromBricksetSet(brickset.GetSetResponseItem{Number: "75192", NumberVariant: 1})
+	setV3 := FromBricksetSetV3(brickset.Set{Number: "75192", NumberVariant: 1})
+	for _, r := range []ItemRef{wanted, set, setV3} {
+		if !r.Same(store) {
+			t.Errorf("expected %v to match %v", r, store)
+		}
+	}
+	if store.Key() != "S:75192-1" {
+		t.Errorf("unexpected key %s", store.Key())
+	}
+
+	part := FromWantedItem(bricklinkuser.WantedItem{ItemNumber: "3024", ItemType: bricklinkuser.ItemTypePart, ColorID: 69})
+	brick, exact, err := FromBrick(legobap.Brick{ItemNo: 4211098, DesignID: 3024, ColorDescription: "SAND YELLOW", ColorLikeDescription: "Yellow"})
+	if err != nil || !exact || !brick.Same(part) || brick.ElementID != 4211098 {
+		t.Errorf("expected %+v to match %+v: %v", brick, part, err)
+	}
+	if _, _, err := FromBrick(legobap.Brick{ItemNo: 4211098}); err == nil {
+		t.Error("expected error for element without design ID")
+	}
+	if s := part.String(); s != "Part 3024 in Dark Tan" {
+		t.Errorf("unexpected string %q", s)
+	}
+}
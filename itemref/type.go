@@ -0,0 +1,97 @@
+package itemref
+
+import (
+	"strings"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+	"github.com/andrewarchi/brick-apis/bricklinkuser"
+)
+
+// Type is a BrickLink item type letter as used by bricklinkuser.ItemType.
+type Type string
+
+// BrickLink item types.
+const (
+	Set         Type = "S"
+	Part        Type = "P"
+	Minifig     Type = "M"
+	Book        Type = "B"
+	Gear        Type = "G"
+	Catalog     Type = "C"
+	Instruction Type = "I"
+	OriginalBox Type = "O"
+	UnsortedLot Type = "U"
+)
+
+var storeTypes = []struct {
+	t     Type
+	store bricklinkstore.ItemType
+}{
+	{Set, bricklinkstore.ItemTypeSet},
+	{Part, bricklinkstore.ItemTypePart},
+	{Minifig, bricklinkstore.ItemTypeMinifig},
+	{Book, bricklinkstore.ItemTypeBook},
+	{Gear, bricklinkstore.ItemTypeGear},
+	{Catalog, bricklinkstore.ItemTypeCatalog},
+	{Instruction, bricklinkstore.ItemTypeInstruction},
+	{OriginalBox, bricklinkstore.ItemTypeOriginalBox},
+	{UnsortedLot, bricklinkstore.ItemTypeUnsortedLot},
+}
+
+// FromStoreType converts a BrickLink store API type name such as "PART".
+// It returns "" for unknown types.
+func FromStoreType(t bricklinkstore.ItemType) Type {
+	for _, st := range storeTypes {
+		if st.store == t {
+			return st.t
+		}
+	}
+	return ""
+}
+
+// FromUserType converts a bricklinkuser item type letter.
+func FromUserType(t bricklinkuser.ItemType) Type {
+	return Type(strings.ToUpper(string(t)))
+}
+
+// ParseType parses a type letter ("P"), store API name ("PART") or display
+// name ("Part" or "Parts"), ignoring case. It returns "" when s is not a
+// known type.
+func ParseType(s string) Type {
+	s = strings.TrimSpace(s)
+	for _, st := range storeTypes {
+		switch {
+		case strings.EqualFold(s, string(st.t)),
+			strings.EqualFold(s, string(st.store)),
+			strings.EqualFold(s, st.t.Name()),
+			strings.EqualFold(s, st.t.PluralName()):
+			return st.t
+		}
+	}
+	return ""
+}
+
+// StoreType returns the BrickLink store API type name, such as "PART".
+func (t Type) StoreType() bricklinkstore.ItemType {
+	for _, st := range storeTypes {
+		if st.t == t {
+			return st.store
+		}
+	}
+	return ""
+}
+
+// UserType returns the bricklinkuser item type letter.
+func (t Type) UserType() bricklinkuser.ItemType {
+	return bricklinkuser.ItemType(t)
+}
+
+// Name returns the singular display name, such as "Part".
+func (t Type) Name() string {
+	return t.UserType().Name()
+}
+
+// PluralName returns the plural display name, such as "Parts".
+func (t Type) PluralName() string {
+	return t.UserType().PluralName()
+}
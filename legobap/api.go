@@ -1,61 +1,133 @@
 package legobap
 
 import (
+	"bufio"
 	"encoding/json"
-	"fmt"
-	"log"
 	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andrewarchi/brick-apis/internal/pool"
 )
 
+const baseURL = "https://www.lego.com"
+
 type LegoBAPClient struct {
-	age     int
-	country CountryCode
+	age      int
+	country  CountryCode
+	locale   string
+	baseURL  string
+	client   *http.Client
+	workers  int
+	throttle *pool.Throttle
 }
 
+// NewClient creates a Bricks & Pieces client for a country using its
+// default locale, or en-US when the country has none. The age and country
+// are not validated; use NewClientWithConfig to validate them.
 func NewClient(age int, country CountryCode) *LegoBAPClient {
-	return &LegoBAPClient{age, country}
+	locale := country.Locale()
+	if locale == "" {
+		locale = "en-US"
+	}
+	return &LegoBAPClient{
+		age:      age,
+		country:  country,
+		locale:   locale,
+		baseURL:  baseURL,
+		client:   http.DefaultClient,
+		workers:  defaultWorkers,
+		throttle: pool.NewThrottle(defaultDelay),
+	}
 }
 
 func (c *LegoBAPClient) GetPart(id string) (*ProductInformation, error) {
-	url := "https://www.lego.com/en-US/service/rpservice/getitemordesign?itemordesignnumber=" + id + "&isSalesFlow=true"
+	u := c.baseURL + "/" + c.locale + "/service/rpservice/getitemordesign?itemordesignnumber=" + url.QueryEscape(id) + "&isSalesFlow=true"
 	var part ProductInformation
-	if err := c.doGet(url, &part); err != nil {
+	if err := c.doGet(u, &part); err != nil {
 		return nil, err
 	}
+	if len(part.Bricks) == 0 && part.UnavailableInformation == nil {
+		return nil, &Error{URL: u, StatusCode: http.StatusOK, Err: ErrItemNotFound}
+	}
 	return &part, nil
 }
 
 func (c *LegoBAPClient) GetSet(id string) (*ProductInformation, error) {
-	url := "https://www.lego.com/en-US/service/rpservice/getproduct?productnumber=" + id + "&isSalesFlow=true"
+	u := c.baseURL + "/" + c.locale + "/service/rpservice/getproduct?productnumber=" + url.QueryEscape(id) + "&isSalesFlow=true"
 	var set ProductInformation
-	if err := c.doGet(url, &set); err != nil {
+	if err := c.doGet(u, &set); err != nil {
 		return nil, err
 	}
+	if set.Product.ProductNo == "" && len(set.Bricks) == 0 && set.UnavailableInformation == nil {
+		return nil, &Error{URL: u, StatusCode: http.StatusOK, Err: ErrItemNotFound}
+	}
 	return &set, nil
 }
 
 func (c *LegoBAPClient) doGet(url string, v interface{}) error {
 	request, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
-	cookie := fmt.Sprintf(`csAgeAndCountry={"age":"%d","countrycode":"%s"}`, c.age, c.country)
-	request.Header.Add("Cookie", cookie)
-	resp, err := http.DefaultClient.Do(request)
+	return c.do(request, v, true)
+}
+
+// do sends a request with the age gate cookie and decodes the JSON
+// response. When strict is set, fields missing from v are an error.
+func (c *LegoBAPClient) do(request *http.Request, v interface{}, strict bool) error {
+	url := request.URL.String()
+	// Set directly since http.Cookie would strip the quotes from the JSON value.
+	request.Header.Add("Cookie", "csAgeAndCountry="+c.ageAndCountryCookie())
+	resp, err := c.client.Do(request)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	decoder := json.NewDecoder(resp.Body)
-	decoder.DisallowUnknownFields()
+	contentType := resp.Header.Get("Content-Type")
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return &Error{url, resp.StatusCode, contentType, ErrItemNotFound}
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnavailableForLegalReasons:
+		return &Error{url, resp.StatusCode, contentType, ErrGeoBlocked}
+	case resp.StatusCode/100 != 2:
+		return &Error{url, resp.StatusCode, contentType, nil}
+	}
+	body := bufio.NewReader(resp.Body)
+	if !strings.Contains(contentType, "json") {
+		if b, err := peekNonSpace(body); err != nil || (b != '{' && b != '[') {
+			return &Error{url, resp.StatusCode, contentType, ErrNotJSON}
+		}
+	}
+	decoder := json.NewDecoder(body)
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
 	return decoder.Decode(v)
 }
 
+// peekNonSpace returns the first byte that is not whitespace without
+// consuming it.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\n', '\r':
+			r.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
 type ProductInformation struct {
-	Product                Product     `json:"Product"`
-	Bricks                 []Brick     `json:"Bricks"`
-	ImageBaseURL           string      `json:"ImageBaseUrl"`
-	UnavailableInformation interface{} `json:"UnAvailableInformation"`
+	Product                Product                 `json:"Product"`
+	Bricks                 []Brick                 `json:"Bricks"`
+	ImageBaseURL           string                  `json:"ImageBaseUrl"`
+	UnavailableInformation *UnavailableInformation `json:"UnAvailableInformation"`
 }
 
 type Brick struct {
@@ -0,0 +1,132 @@
+package legobap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SetAvailability summarizes which elements of a set can be bought through
+// Bricks & Pieces.
+type SetAvailability struct {
+	Product     Product
+	Available   []Brick            // Buyable in the quantity needed for the set
+	Limited     []Brick            // Buyable, but MaxQty is below the quantity in the set
+	Unavailable []UnavailableBrick // Not buyable
+	Licensed    []Brick            // IP (licensed) elements from any group
+	Cost        float64            // Cost of the buyable elements, up to MaxQty each
+	CurrencyID  string             // Currency of Cost
+}
+
+// UnavailableBrick is an element that cannot be bought, with its reason
+// categorized.
+type UnavailableBrick struct {
+	Brick
+	Reason UnavailableCategory
+}
+
+// UnavailableCategory is a broad reason for an element being unavailable.
+type UnavailableCategory int
+
+// Categories of UnavailableReason.ReasonText.
+const (
+	UnavailableOther      UnavailableCategory = iota
+	UnavailableOutOfStock                     // Temporarily sold out
+	UnavailableRetired                        // No longer produced
+	UnavailableRestricted                     // Not sold in the country
+)
+
+func (c UnavailableCategory) String() string {
+	switch c {
+	case UnavailableOutOfStock:
+		return "out of stock"
+	case UnavailableRetired:
+		return "retired"
+	case UnavailableRestricted:
+		return "restricted"
+	}
+	return "other"
+}
+
+var reasonKeywords = []struct {
+	category UnavailableCategory
+	keywords []string
+}{
+	{UnavailableRestricted, []string{"country", "region", "market", "restricted"}},
+	{UnavailableRetired, []string{"no longer", "retired", "discontinued", "not be available again"}},
+	{UnavailableOutOfStock, []string{"out of stock", "sold out", "temporarily", "back soon"}},
+}
+
+// CategorizeReason categorizes the reason an element is unavailable from
+// its reason text and restricted markets.
+func CategorizeReason(reason *UnavailableReason) UnavailableCategory {
+	if reason == nil {
+		return UnavailableOther
+	}
+	if len(reason.RestrictedMarkets) != 0 {
+		return UnavailableRestricted
+	}
+	text := strings.ToLower(reason.ReasonText)
+	for _, r := range reasonKeywords {
+		for _, keyword := range r.keywords {
+			if strings.Contains(text, keyword) {
+				return r.category
+			}
+		}
+	}
+	return UnavailableOther
+}
+
+// GetSetAvailability gets the elements of a set and summarizes which can be
+// bought.
+func (c *LegoBAPClient) GetSetAvailability(setNo string) (*SetAvailability, error) {
+	set, err := c.GetSet(setNo)
+	if err != nil {
+		return nil, err
+	}
+	a, err := Availability(set)
+	if err != nil {
+		return nil, err
+	}
+	if a.CurrencyID != "" && a.CurrencyID != c.Currency() {
+		return nil, fmt.Errorf("legobap: prices in %s, expected %s for %s", a.CurrencyID, c.Currency(), c.country)
+	}
+	return a, nil
+}
+
+// Availability summarizes which elements of a product can be bought. It
+// returns an error when the buyable elements are priced in more than one
+// currency.
+func Availability(info *ProductInformation) (*SetAvailability, error) {
+	a := &SetAvailability{Product: info.Product}
+	for _, b := range info.Bricks {
+		if b.IP {
+			a.Licensed = append(a.Licensed, b)
+		}
+		if b.ItemUnavailable || b.MaxQty <= 0 {
+			a.Unavailable = append(a.Unavailable, UnavailableBrick{b, CategorizeReason(b.UnavailableReason)})
+			continue
+		}
+		qty := b.SQty
+		if b.MaxQty < b.SQty {
+			a.Limited = append(a.Limited, b)
+			qty = b.MaxQty
+		} else {
+			a.Available = append(a.Available, b)
+		}
+		if b.CurrencyID != "" {
+			if a.CurrencyID == "" {
+				a.CurrencyID = b.CurrencyID
+			} else if b.CurrencyID != a.CurrencyID {
+				return nil, fmt.Errorf("legobap: item %d priced in %s, expected %s", b.ItemNo, b.CurrencyID, a.CurrencyID)
+			}
+		}
+		a.Cost += b.Price * float64(qty)
+	}
+	return a, nil
+}
+
+// Complete reports whether every element of the set can be bought in the
+// quantity needed.
+func (a *SetAvailability) Complete() bool {
+	return len(a.Limited) == 0 && len(a.Unavailable) == 0
+}
@@ -0,0 +1,45 @@
+package legobap
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAvailability(t *testing.T) {
+	info := &ProductInformation{
+		Product: Product{ProductNo: "10696"},
+		Bricks: []Brick{
+			{ItemNo: 300121, Price: 0.2, CurrencyID: "USD", SQty: 4, MaxQty: 200},
+			{ItemNo: 4211389, Price: 0.1, CurrencyID: "USD", SQty: 10, MaxQty: 6},
+			{ItemNo: 6028736, Price: 1.5, CurrencyID: "USD", SQty: 1, MaxQty: 10, IP: true},
+			{ItemNo: 4558886, SQty: 2, ItemUnavailable: true, UnavailableReason: &UnavailableReason{ReasonText: "This item is temporarily out of stock"}},
+			{ItemNo: 4107783, SQty: 1, ItemUnavailable: true, UnavailableReason: &UnavailableReason{ReasonText: "Not available in your country", RestrictedMarkets: []interface{}{"US"}}},
+			{ItemNo: 4107784, SQty: 1, ItemUnavailable: true},
+		},
+	}
+	a, err := Availability(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Available) != 2 || len(a.Limited) != 1 || len(a.Unavailable) != 3 || len(a.Licensed) != 1 {
+		t.Errorf("unexpected groups %d available, %d limited, %d unavailable, %d licensed",
+			len(a.Available), len(a.Limited), len(a.Unavailable), len(a.Licensed))
+	}
+	if want := 4*0.2 + 6*0.1 + 1.5; math.Abs(a.Cost-want) > 1e-9 || a.CurrencyID != "USD" {
+		t.Errorf("expected cost USD %v, but got %s %v", want, a.CurrencyID, a.Cost)
+	}
+	reasons := []UnavailableCategory{UnavailableOutOfStock, UnavailableRestricted, UnavailableOther}
+	for i, u := range a.Unavailable {
+		if u.Reason != reasons[i] {
+			t.Errorf("item %d: expected reason %s, but got %s", u.ItemNo, reasons[i], u.Reason)
+		}
+	}
+	if a.Complete() {
+		t.Error("expected incomplete set")
+	}
+
+	info.Bricks = append(info.Bricks, Brick{ItemNo: 1, Price: 1, CurrencyID: "EUR", SQty: 1, MaxQty: 1})
+	if _, err := Availability(info); err == nil {
+		t.Error("expected mixed currency error")
+	}
+}
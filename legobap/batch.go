@@ -0,0 +1,85 @@
+package legobap
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/internal/pool"
+)
+
+const (
+	defaultWorkers = 4
+	defaultDelay   = 250 * time.Millisecond
+)
+
+// SetConcurrency sets the number of requests GetParts makes in parallel and
+// the minimum delay between the starts of its requests to the same host.
+// Other requests are not throttled.
+func (c *LegoBAPClient) SetConcurrency(workers int, delay time.Duration) {
+	if workers < 1 {
+		workers = 1
+	}
+	c.workers = workers
+	c.throttle = pool.NewThrottle(delay)
+}
+
+// PartsResult is the result of looking up many element or design IDs.
+type PartsResult struct {
+	Bricks []Brick          // Bricks found for any ID, merged by ItemNo
+	Errors map[string]error // Errors by ID, for IDs that failed
+	ByID   map[string][]int // ItemNos found for each successful ID
+}
+
+// GetParts looks up element or design IDs with a bounded number of
+// parallel requests. Duplicate IDs are requested once and a failed ID does
+// not stop the others.
+func (c *LegoBAPClient) GetParts(ids []string) *PartsResult {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	type result struct {
+		info *ProductInformation
+		err  error
+	}
+	results := make([]result, len(unique))
+	host := c.host()
+	pool.Run(len(unique), c.workers, func(i int) {
+		c.throttle.Wait(host)
+		info, err := c.GetPart(unique[i])
+		results[i] = result{info, err}
+	})
+
+	r := &PartsResult{Errors: make(map[string]error), ByID: make(map[string][]int)}
+	merged := make(map[int]bool)
+	for i, id := range unique {
+		if results[i].err != nil {
+			r.Errors[id] = results[i].err
+			continue
+		}
+		itemNos := []int{}
+		for _, b := range results[i].info.Bricks {
+			itemNos = append(itemNos, b.ItemNo)
+			if !merged[b.ItemNo] {
+				merged[b.ItemNo] = true
+				r.Bricks = append(r.Bricks, b)
+			}
+		}
+		r.ByID[id] = itemNos
+	}
+	return r
+}
+
+// host returns the host that requests are made to, for the throttle.
+func (c *LegoBAPClient) host() string {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return c.baseURL
+	}
+	return u.Host
+}
@@ -0,0 +1,46 @@
+package legobap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetParts(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("itemordesignnumber")
+		mu.Lock()
+		requests[id]++
+		mu.Unlock()
+		switch id {
+		case "3024":
+			w.Write([]byte(`{"Product":{},"Bricks":[{"ItemNo":302401,"SQty":1},{"ItemNo":302421,"SQty":1}]}`))
+		case "302401":
+			w.Write([]byte(`{"Product":{},"Bricks":[{"ItemNo":302401,"SQty":1}]}`))
+		default:
+			w.Write([]byte(`not json`))
+		}
+	}))
+	defer server.Close()
+	c := NewClient(18, CountryCodeUS)
+	c.baseURL = server.URL
+	c.SetConcurrency(2, time.Millisecond)
+
+	r := c.GetParts([]string{"3024", "302401", "bad id", "3024"})
+	if len(r.Bricks) != 2 || r.Bricks[0].ItemNo != 302401 || r.Bricks[1].ItemNo != 302421 {
+		t.Errorf("unexpected merged bricks %+v", r.Bricks)
+	}
+	if len(r.Errors) != 1 || r.Errors["bad id"] == nil {
+		t.Errorf("expected error for bad id, but got %v", r.Errors)
+	}
+	if len(r.ByID["3024"]) != 2 || len(r.ByID["302401"]) != 1 {
+		t.Errorf("unexpected item numbers by ID %v", r.ByID)
+	}
+	if requests["3024"] != 1 {
+		t.Errorf("expected duplicate IDs to be requested once, but got %d", requests["3024"])
+	}
+}
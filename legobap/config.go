@@ -0,0 +1,111 @@
+package legobap
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Config configures a Bricks & Pieces client.
+type Config struct {
+	Age     int         // Age given to the age gate, at least MinAge
+	Country CountryCode // Country to buy from, which sets the currency
+	Locale  string      // Site locale such as "de-DE", defaulting to the country's locale
+}
+
+type countryInfo struct {
+	Locale   string
+	Currency string
+}
+
+// Default locales and currencies of the countries that can purchase
+// through Bricks & Pieces.
+var countries = map[CountryCode]countryInfo{
+	CountryCodeAU: {"en-AU", "AUD"},
+	CountryCodeAT: {"de-AT", "EUR"},
+	CountryCodeBE: {"fr-BE", "EUR"},
+	CountryCodeCA: {"en-CA", "CAD"},
+	CountryCodeCZ: {"cs-CZ", "CZK"},
+	CountryCodeDK: {"da-DK", "DKK"},
+	CountryCodeFI: {"fi-FI", "EUR"},
+	CountryCodeFR: {"fr-FR", "EUR"},
+	CountryCodeDE: {"de-DE", "EUR"},
+	CountryCodeHU: {"hu-HU", "HUF"},
+	CountryCodeIE: {"en-IE", "EUR"},
+	CountryCodeIT: {"it-IT", "EUR"},
+	CountryCodeLU: {"fr-LU", "EUR"},
+	CountryCodeNL: {"nl-NL", "EUR"},
+	CountryCodeNZ: {"en-NZ", "NZD"},
+	CountryCodeNO: {"no-NO", "NOK"},
+	CountryCodePL: {"pl-PL", "PLN"},
+	CountryCodePT: {"pt-PT", "EUR"},
+	CountryCodeES: {"es-ES", "EUR"},
+	CountryCodeSE: {"sv-SE", "SEK"},
+	CountryCodeCH: {"de-CH", "CHF"},
+	CountryCodeGB: {"en-GB", "GBP"},
+	CountryCodeUS: {"en-US", "USD"},
+}
+
+// Supported reports whether the country can purchase through Bricks & Pieces.
+func (c CountryCode) Supported() bool {
+	_, ok := countries[c]
+	return ok
+}
+
+// Currency returns the ISO 4217 code of the currency prices are given in
+// for the country, or "" when the country is not supported.
+func (c CountryCode) Currency() string {
+	return countries[c].Currency
+}
+
+// Locale returns the default site locale of the country, or "" when the
+// country is not supported.
+func (c CountryCode) Locale() string {
+	return countries[c].Locale
+}
+
+var localeRe = regexp.MustCompile(`^[a-z]{2}-[A-Z]{2}$`)
+
+// Validate checks that the country can purchase through Bricks & Pieces,
+// that the age passes the age gate and that the locale is well formed.
+func (cfg Config) Validate() error {
+	if !cfg.Country.Supported() {
+		return &Error{Err: ErrUnsupportedCountry}
+	}
+	if cfg.Age < MinAge {
+		return &Error{Err: ErrUnderage}
+	}
+	if cfg.Locale != "" && !localeRe.MatchString(cfg.Locale) {
+		return fmt.Errorf("legobap: invalid locale %q", cfg.Locale)
+	}
+	return nil
+}
+
+// NewClientWithConfig creates a client after validating the config.
+func NewClientWithConfig(cfg Config) (*LegoBAPClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	c := NewClient(cfg.Age, cfg.Country)
+	if cfg.Locale != "" {
+		c.locale = cfg.Locale
+	}
+	return c, nil
+}
+
+// Currency returns the currency prices are given in for the client's
+// country.
+func (c *LegoBAPClient) Currency() string {
+	return c.country.Currency()
+}
+
+// ageAndCountryCookie is the value of the csAgeAndCountry cookie set by
+// the age gate.
+func (c *LegoBAPClient) ageAndCountryCookie() string {
+	data, _ := json.Marshal(struct {
+		Age         string      `json:"age"`
+		CountryCode CountryCode `json:"countrycode"`
+	}{strconv.Itoa(c.age), c.country})
+	return string(data)
+}
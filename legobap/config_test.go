@@ -0,0 +1,52 @@
+package legobap
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfig(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want error
+	}{
+		{Config{Age: 30, Country: CountryCodeDE}, nil},
+		{Config{Age: 30, Country: "JP"}, ErrUnsupportedCountry},
+		{Config{Age: 10, Country: CountryCodeDE}, ErrUnderage},
+	}
+	for _, test := range tests {
+		if err := test.cfg.Validate(); !errors.Is(err, test.want) {
+			t.Errorf("%+v: expected %v, but got %v", test.cfg, test.want, err)
+		}
+	}
+	if err := (Config{Age: 30, Country: CountryCodeDE, Locale: "german"}).Validate(); err == nil {
+		t.Error("expected invalid locale error")
+	}
+	if CountryCodeNO.Currency() != "NOK" || CountryCodeFR.Currency() != "EUR" || CountryCode("JP").Currency() != "" {
+		t.Error("unexpected currencies")
+	}
+}
+
+func TestLocaleAndCookie(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/nl-BE/service/rpservice/getitemordesign" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if cookie := r.Header.Get("Cookie"); cookie != `csAgeAndCountry={"age":"30","countrycode":"BE"}` {
+			t.Errorf("unexpected cookie %s", cookie)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Product":{},"Bricks":[{"ItemNo":302401,"CId":"EUR"}]}`))
+	}))
+	defer server.Close()
+	c, err := NewClientWithConfig(Config{Age: 30, Country: CountryCodeBE, Locale: "nl-BE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseURL = server.URL
+	if _, err := c.GetPart("3024"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,97 @@
+package legobap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Errors reported by requests to Bricks & Pieces and, for the country and
+// age, by Config.Validate. They are wrapped in an *Error and can be tested
+// with errors.Is.
+var (
+	ErrUnsupportedCountry = errors.New("country is not supported by Bricks & Pieces")
+	ErrUnderage           = errors.New("age is below the minimum for Bricks & Pieces")
+	ErrItemNotFound       = errors.New("item not found")
+	ErrGeoBlocked         = errors.New("request was blocked for this location")
+	ErrNotJSON            = errors.New("response is not JSON")
+)
+
+// MinAge is the minimum age accepted by the Bricks & Pieces age gate. The
+// LEGO Shop terms of sale require customers placing orders to be 18 or
+// older.
+const MinAge = 18
+
+// Error is an error from a request to Bricks & Pieces.
+type Error struct {
+	URL         string
+	StatusCode  int    // HTTP status, or zero when no request was made
+	ContentType string // Content type of the response
+	Err         error  // One of the Err values, or nil for other HTTP errors
+}
+
+func (e *Error) Error() string {
+	msg := "status " + strconv.Itoa(e.StatusCode)
+	if e.Err != nil {
+		msg = e.Err.Error()
+	}
+	if e.URL == "" {
+		return "legobap: " + msg
+	}
+	return fmt.Sprintf("legobap: %s: %s", e.URL, msg)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// UnavailableInformation explains why a product cannot be bought. The
+// format is undocumented, so fields other than the known ones are kept in
+// Extra.
+type UnavailableInformation struct {
+	Title             string
+	ReasonText        string
+	LinkText          string
+	Link              *UnavailableLink
+	RestrictedMarkets []string
+	Extra             map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes the known fields of an object, or the reason text
+// when the information is a plain string.
+func (u *UnavailableInformation) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*u = UnavailableInformation{ReasonText: text}
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*u = UnavailableInformation{}
+	known := []struct {
+		key string
+		v   interface{}
+	}{
+		{"Title", &u.Title},
+		{"ReasonText", &u.ReasonText},
+		{"LinkText", &u.LinkText},
+		{"Link", &u.Link},
+		{"RestrictedMarkets", &u.RestrictedMarkets},
+	}
+	for _, k := range known {
+		if raw, ok := fields[k.key]; ok {
+			if err := json.Unmarshal(raw, k.v); err != nil {
+				return fmt.Errorf("UnAvailableInformation.%s: %v", k.key, err)
+			}
+			delete(fields, k.key)
+		}
+	}
+	if len(fields) != 0 {
+		u.Extra = fields
+	}
+	return nil
+}
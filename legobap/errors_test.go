@@ -0,0 +1,72 @@
+package legobap
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("itemordesignnumber") {
+		case "blocked":
+			w.WriteHeader(http.StatusForbidden)
+		case "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "empty":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"Product":{},"Bricks":[]}`))
+		case "html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<!DOCTYPE html><html></html>`))
+		}
+	}))
+	defer server.Close()
+	c := NewClient(MinAge, CountryCodeUS)
+	c.baseURL = server.URL
+
+	tests := []struct {
+		id   string
+		want error
+	}{
+		{"blocked", ErrGeoBlocked},
+		{"missing", ErrItemNotFound},
+		{"empty", ErrItemNotFound},
+		{"html", ErrNotJSON},
+	}
+	for _, test := range tests {
+		_, err := c.GetPart(test.id)
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s: expected %v, but got %v", test.id, test.want, err)
+		}
+	}
+
+	if _, err := NewClientWithConfig(Config{Age: MinAge, Country: "JP"}); !errors.Is(err, ErrUnsupportedCountry) {
+		t.Errorf("expected unsupported country, but got %v", err)
+	}
+	if _, err := NewClientWithConfig(Config{Age: 12, Country: CountryCodeUS}); !errors.Is(err, ErrUnderage) {
+		t.Errorf("expected underage, but got %v", err)
+	}
+	// Clients created without a config are not validated.
+	c.age = 0
+	if _, err := c.GetPart("empty"); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected request to be made, but got %v", err)
+	}
+}
+
+func TestUnmarshalUnavailableInformation(t *testing.T) {
+	var info ProductInformation
+	data := `{"Product":{},"Bricks":[],"UnAvailableInformation":{"Title":"Sold out","ReasonText":"Not available","RestrictedMarkets":["US"],"Code":7}}`
+	if err := json.Unmarshal([]byte(data), &info); err != nil {
+		t.Fatal(err)
+	}
+	u := info.UnavailableInformation
+	if u == nil || u.Title != "Sold out" || u.ReasonText != "Not available" || len(u.RestrictedMarkets) != 1 || string(u.Extra["Code"]) != "7" {
+		t.Errorf("unexpected information %+v", u)
+	}
+	if err := json.Unmarshal([]byte(`{"UnAvailableInformation":"Retired"}`), &info); err != nil || info.UnavailableInformation.ReasonText != "Retired" {
+		t.Errorf("unexpected information %+v, %v", info.UnavailableInformation, err)
+	}
+}
@@ -0,0 +1,8 @@
+package legobap
+
+import "github.com/andrewarchi/brick-apis/money"
+
+// Money returns the price of the element in its currency.
+func (b *Brick) Money() money.Money {
+	return money.New(money.FromFloat(b.Price), money.Currency(b.CurrencyID))
+}
@@ -0,0 +1,254 @@
+package legobap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Pick a Brick is the LEGO store for new elements. It has a different
+// catalog, prices and order limits to Bricks & Pieces and is queried
+// through the GraphQL API of lego.com.
+
+const pabQuery = `query PickABrickQuery($query: String, $page: Int, $perPage: Int) {
+  searchElements(query: $query, page: $page, perPage: $perPage) {
+    total
+    results {
+      id
+      name
+      variant {
+        id
+        price {
+          centAmount
+          currencyCode
+          formattedAmount
+        }
+        attributes {
+          designNumber
+          colour
+          colourFamily
+          maxOrderQuantity
+          availabilityStatus
+          deliveryChannel
+        }
+      }
+    }
+  }
+}`
+
+const pabPerPage = 100
+
+// pabAvailable is the availabilityStatus of elements that can be ordered.
+const pabAvailable = "E_AVAILABLE"
+
+type pabRequest struct {
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+	Query         string                 `json:"query"`
+}
+
+type pabResponse struct {
+	Data struct {
+		SearchElements struct {
+			Total   int          `json:"total"`
+			Results []PABElement `json:"results"`
+		} `json:"searchElements"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// PABElement is an element returned by a Pick a Brick search.
+type PABElement struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Variant struct {
+		ID    string `json:"id"`
+		Price struct {
+			CentAmount      int    `json:"centAmount"`
+			CurrencyCode    string `json:"currencyCode"`
+			FormattedAmount string `json:"formattedAmount"`
+		} `json:"price"`
+		Attributes struct {
+			DesignNumber       int    `json:"designNumber"`
+			Colour             string `json:"colour"`
+			ColourFamily       string `json:"colourFamily"`
+			MaxOrderQuantity   int    `json:"maxOrderQuantity"`
+			AvailabilityStatus string `json:"availabilityStatus"`
+			DeliveryChannel    string `json:"deliveryChannel"`
+		} `json:"attributes"`
+	} `json:"variant"`
+}
+
+// Brick converts the element to the shape of a Bricks & Pieces brick.
+func (e *PABElement) Brick() Brick {
+	itemNo, _ := strconv.Atoi(e.ID)
+	a := e.Variant.Attributes
+	price := e.Variant.Price
+	unavailable := a.AvailabilityStatus != pabAvailable
+	b := Brick{
+		ItemNo:               itemNo,
+		ItemDescription:      e.Name,
+		ColorDescription:     a.Colour,
+		ColorLikeDescription: a.ColourFamily,
+		MaxQty:               a.MaxOrderQuantity,
+		Price:                float64(price.CentAmount) / 100,
+		CurrencyID:           price.CurrencyCode,
+		DesignID:             a.DesignNumber,
+		PriceStr:             price.FormattedAmount,
+		ItemUnavailable:      unavailable,
+	}
+	if unavailable {
+		b.UnavailableReason = &UnavailableReason{ReasonText: a.AvailabilityStatus}
+	}
+	return b
+}
+
+// SearchPickABrick searches Pick a Brick for an element or design ID and
+// returns the matching elements as bricks. Search results that match
+// neither ID exactly are dropped.
+func (c *LegoBAPClient) SearchPickABrick(id string) ([]Brick, error) {
+	body, err := json.Marshal(pabRequest{
+		OperationName: "PickABrickQuery",
+		Variables:     map[string]interface{}{"query": id, "page": 1, "perPage": pabPerPage},
+		Query:         pabQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("POST", c.baseURL+"/api/graphql/PickABrickQuery", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Locale", c.locale)
+	var r pabResponse
+	if err := c.do(request, &r, false); err != nil {
+		return nil, err
+	}
+	if len(r.Errors) != 0 {
+		msgs := make([]string, len(r.Errors))
+		for i, e := range r.Errors {
+			msgs[i] = e.Message
+		}
+		return nil, fmt.Errorf("legobap: %s: %s", request.URL, strings.Join(msgs, "; "))
+	}
+	var bricks []Brick
+	for i := range r.Data.SearchElements.Results {
+		b := r.Data.SearchElements.Results[i].Brick()
+		if strconv.Itoa(b.ItemNo) == id || strconv.Itoa(b.DesignID) == id {
+			bricks = append(bricks, b)
+		}
+	}
+	if len(bricks) == 0 {
+		return nil, &Error{URL: request.URL.String(), StatusCode: http.StatusOK, Err: ErrItemNotFound}
+	}
+	return bricks, nil
+}
+
+// Source is a LEGO store that sells elements.
+type Source int
+
+// Sources of elements.
+const (
+	SourceBricksAndPieces Source = iota
+	SourcePickABrick
+)
+
+func (s Source) String() string {
+	if s == SourcePickABrick {
+		return "Pick a Brick"
+	}
+	return "Bricks & Pieces"
+}
+
+// Comparison is an element as offered by Bricks & Pieces and Pick a Brick.
+// Either is nil when the element was not found there.
+type Comparison struct {
+	ItemNo          int
+	BricksAndPieces *Brick
+	PickABrick      *Brick
+}
+
+// Cheapest returns the source with the lower price among those where the
+// element can be bought. It reports false when neither sells it.
+func (c *Comparison) Cheapest() (*Brick, Source, bool) {
+	bap, pab := buyable(c.BricksAndPieces), buyable(c.PickABrick)
+	switch {
+	case bap != nil && (pab == nil || bap.Price <= pab.Price):
+		return bap, SourceBricksAndPieces, true
+	case pab != nil:
+		return pab, SourcePickABrick, true
+	}
+	return nil, 0, false
+}
+
+func buyable(b *Brick) *Brick {
+	if b == nil || b.ItemUnavailable || b.MaxQty <= 0 {
+		return nil
+	}
+	return b
+}
+
+// CompareError holds the errors of looking up an ID in each source. A
+// source that does not sell the element reports ErrItemNotFound, which can
+// be told apart from failed requests with errors.Is. Either is nil when
+// its lookup succeeded.
+type CompareError struct {
+	BricksAndPieces error
+	PickABrick      error
+}
+
+func (e *CompareError) Error() string {
+	var msgs []string
+	if e.BricksAndPieces != nil {
+		msgs = append(msgs, SourceBricksAndPieces.String()+": "+e.BricksAndPieces.Error())
+	}
+	if e.PickABrick != nil {
+		msgs = append(msgs, SourcePickABrick.String()+": "+e.PickABrick.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Compare looks up element or design IDs in Bricks & Pieces and Pick a
+// Brick and pairs the results by ItemNo. An ID is reported in errs when
+// either lookup fails, with the error of each source. Pick a Brick
+// searches are throttled like GetParts.
+func (c *LegoBAPClient) Compare(ids []string) (comparisons []Comparison, errs map[string]*CompareError) {
+	bap := c.GetParts(ids)
+	host := c.host()
+	index := make(map[int]int)
+	get := func(itemNo int) *Comparison {
+		i, ok := index[itemNo]
+		if !ok {
+			i = len(comparisons)
+			index[itemNo] = i
+			comparisons = append(comparisons, Comparison{ItemNo: itemNo})
+		}
+		return &comparisons[i]
+	}
+	for i := range bap.Bricks {
+		get(bap.Bricks[i].ItemNo).BricksAndPieces = &bap.Bricks[i]
+	}
+	errs = make(map[string]*CompareError)
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		c.throttle.Wait(host)
+		bricks, err := c.SearchPickABrick(id)
+		if bapErr := bap.Errors[id]; bapErr != nil || err != nil {
+			errs[id] = &CompareError{BricksAndPieces: bapErr, PickABrick: err}
+		}
+		for i := range bricks {
+			get(bricks[i].ItemNo).PickABrick = &bricks[i]
+		}
+	}
+	return comparisons, errs
+}
@@ -0,0 +1,80 @@
+package legobap
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/en-US/service/rpservice/getitemordesign":
+			switch r.URL.Query().Get("itemordesignnumber") {
+			case "3024":
+				w.Write([]byte(`{"Product":{},"Bricks":[{"ItemNo":302401,"DesignId":3024,"Price":0.08,"CId":"USD","MaxQty":200},{"ItemNo":302421,"DesignId":3024,"Price":0.08,"CId":"USD","MaxQty":0,"ItemUnavailable":true}]}`))
+			case "3001":
+				w.Write([]byte(`{"Product":{},"Bricks":[{"ItemNo":300101,"DesignId":3001,"Price":0.20,"CId":"USD","MaxQty":200}]}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "/api/graphql/PickABrickQuery":
+			var req pabRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Error(err)
+			}
+			if r.Header.Get("X-Locale") != "en-US" {
+				t.Errorf("unexpected locale %q", r.Header.Get("X-Locale"))
+			}
+			switch req.Variables["query"] {
+			case "3024":
+				w.Write([]byte(`{"data":{"searchElements":{"total":3,"results":[
+					{"id":"302401","name":"PLATE 1X1","variant":{"price":{"centAmount":10,"currencyCode":"USD"},"attributes":{"designNumber":3024,"colour":"White","maxOrderQuantity":999,"availabilityStatus":"E_AVAILABLE"}}},
+					{"id":"302421","name":"PLATE 1X1","variant":{"price":{"centAmount":10,"currencyCode":"USD"},"attributes":{"designNumber":3024,"colour":"Bright Red","maxOrderQuantity":999,"availabilityStatus":"E_AVAILABLE"}}},
+					{"id":"6252040","name":"PLATE 1X1 W/ CLIP","variant":{"price":{"centAmount":15,"currencyCode":"USD"},"attributes":{"designNumber":15712,"maxOrderQuantity":999,"availabilityStatus":"E_AVAILABLE"}}}]}}}`))
+			case "3001":
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.Write([]byte(`{"data":{"searchElements":{"total":0,"results":[]}}}`))
+			}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+	c := NewClient(MinAge, CountryCodeUS)
+	c.baseURL = server.URL
+	c.SetConcurrency(1, 0)
+
+	comparisons, errs := c.Compare([]string{"3024", "9999", "3001"})
+	if len(errs) != 2 {
+		t.Errorf("expected errors for 9999 and 3001, but got %v", errs)
+	}
+	if e := errs["9999"]; e == nil || !errors.Is(e.BricksAndPieces, ErrItemNotFound) || !errors.Is(e.PickABrick, ErrItemNotFound) {
+		t.Errorf("expected 9999 not found in either source, but got %v", e)
+	}
+	if e := errs["3001"]; e == nil || e.BricksAndPieces != nil || e.PickABrick == nil || errors.Is(e.PickABrick, ErrItemNotFound) {
+		t.Errorf("expected failed Pick a Brick request for 3001, but got %v", e)
+	}
+	if len(comparisons) != 3 {
+		t.Fatalf("expected 3 comparisons, but got %+v", comparisons)
+	}
+	want := []struct {
+		itemNo int
+		source Source
+		price  float64
+	}{
+		{302401, SourceBricksAndPieces, 0.08},
+		{302421, SourcePickABrick, 0.10},
+	}
+	for i, w := range want {
+		cmp := comparisons[i]
+		b, source, ok := cmp.Cheapest()
+		if cmp.ItemNo != w.itemNo || !ok || source != w.source || b.Price != w.price {
+			t.Errorf("%d: expected %s at %v, but got %s at %v", cmp.ItemNo, w.source, w.price, source, b)
+		}
+	}
+}
@@ -8,11 +8,11 @@ import (
 	"net/http"
 	"os"
 	"strconv"
-	"strings"
 
 	"github.com/andrewarchi/brick-apis/bricklinkstore"
 	"github.com/andrewarchi/brick-apis/bricklinkuser"
 	"github.com/andrewarchi/brick-apis/legobap"
+	"github.com/andrewarchi/brick-apis/spend"
 )
 
 var (
@@ -43,17 +43,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	orders, err := blStore.GetOrders("out")
-	if err != nil {
-		fmt.Println(err)
-	}
-	for _, o := range orders {
-		order, err := blStore.GetOrder(o.OrderID)
-		if err != nil {
-			fmt.Println(err)
-		}
-		fmt.Println(order)
-	}
+	reportSpend(blStore)
 
 	items, err := blStore.GetOrderItems(11037590)
 	fmt.Println(items)
@@ -107,57 +97,29 @@ func responseToString(resp *http.Response) (string, error) {
 }
 
 func reportOwnedWantedParts(blUser *bricklinkuser.Client) {
-	var wanted [][]bricklinkuser.WantedItem
-	var sources [][]bricklinkuser.WantedItem
-	defaultList, err := blUser.GetWantedList(0)
+	lists, err := blUser.GetAllWantedLists()
 	if err != nil {
 		log.Fatal(err)
 	}
-	wanted = append(wanted, defaultList.WantedItems)
-	for _, list := range defaultList.WantedLists {
-		if list.ID != 0 {
-			list, err := blUser.GetWantedList(list.ID)
-			if err != nil {
-				log.Fatal(err)
-			}
-			name := list.WantedListInfo.Name
-			if strings.HasPrefix(name, "[LOOSE]") {
-				sources = append(sources, list.WantedItems)
-			} else if !strings.HasPrefix(name, "[IGNORE]") {
-				wanted = append(wanted, list.WantedItems)
-			}
-		}
+	allocs := bricklinkuser.MatchWantedLists(lists, bricklinkuser.DefaultListConventions)
+	if err := bricklinkuser.WriteAllocationsText(os.Stdout, allocs); err != nil {
+		log.Fatal(err)
 	}
-	wantedMap := make(map[string][]bricklinkuser.WantedItem)
-	for _, items := range wanted {
-		for _, item := range items {
-			if item.WantedQty > item.WantedQtyFilled {
-				key := item.ItemNumber + ";" + item.ColorName
-				wantedMap[key] = append(wantedMap[key], item)
-			}
-		}
+}
+
+func reportSpend(blStore *bricklinkstore.Client) {
+	purchases, err := spend.Fetch(blStore)
+	if err != nil {
+		log.Fatal(err)
 	}
-	sourceMap := make(map[string][]bricklinkuser.WantedItem)
-	for _, items := range sources {
-		for _, item := range items {
-			key := item.ItemNumber + ";" + item.ColorName
-			sourceMap[key] = append(sourceMap[key], item)
+	for _, by := range []spend.Dimension{spend.BySeller, spend.ByMonth, spend.ByItemType} {
+		stats, err := spend.Aggregate(purchases, by)
+		if err != nil {
+			log.Fatal(err)
 		}
-	}
-	for _, sourceItems := range sourceMap {
-		item := sourceItems[0]
-		key := item.ItemNumber + ";" + item.ColorName
-		if wantedItems, ok := wantedMap[key]; ok {
-			fmt.Println("Want", item.ItemType, item.ColorName, item.ItemNumber, item.ItemName)
-			for _, wi := range wantedItems {
-				fmt.Printf(" - %d (have %d) in %s\n", wi.WantedQty-wi.WantedQtyFilled, wi.WantedQtyFilled, wi.WantedListName)
-			}
-			fmt.Println("Sources")
-			for _, si := range sourceItems {
-				name := strings.TrimSpace(strings.TrimPrefix(si.WantedListName, "[LOOSE]"))
-				fmt.Printf(" - %d in %s\n", si.WantedQty, name)
-			}
-			fmt.Println()
+		if err := spend.WriteReport(os.Stdout, by, stats); err != nil {
+			log.Fatal(err)
 		}
+		fmt.Println()
 	}
 }
@@ -0,0 +1,201 @@
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Places is the number of decimal places kept by Decimal, the same as
+// BrickLink prices.
+const Places = 4
+
+const scale = 10000
+
+// Decimal is a fixed-point number with 4 decimal places.
+type Decimal int64
+
+// ParseDecimal parses a plain decimal number such as "-1234.5". More than
+// 4 decimal places are rounded half away from zero.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !isDecimal(s) {
+		return 0, fmt.Errorf("money: invalid decimal %q", s)
+	}
+	r, _ := new(big.Rat).SetString(s)
+	return FromRat(r)
+}
+
+// isDecimal reports whether s is an optionally signed decimal number
+// without an exponent.
+func isDecimal(s string) bool {
+	if len(s) != 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	digits, point := 0, false
+	for _, r := range s {
+		switch {
+		case '0' <= r && r <= '9':
+			digits++
+		case r == '.' && !point:
+			point = true
+		default:
+			return false
+		}
+	}
+	return digits != 0
+}
+
+// MustParseDecimal is like ParseDecimal, but panics on error.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// FromInt returns the decimal of an integer.
+func FromInt(n int64) Decimal {
+	return Decimal(n * scale)
+}
+
+// FromFloat returns the decimal closest to f.
+func FromFloat(f float64) Decimal {
+	return Decimal(math.Round(f * scale))
+}
+
+// FromRat rounds a rational number half away from zero to 4 places.
+func FromRat(r *big.Rat) (Decimal, error) {
+	n := new(big.Int).Mul(r.Num(), big.NewInt(scale))
+	d := r.Denom()
+	q, m := new(big.Int).QuoRem(n, d, new(big.Int))
+	if m.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(m), big.NewInt(2)).Cmp(d) >= 0 {
+		q.Add(q, big.NewInt(int64(n.Sign())))
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("money: %s out of range", r.FloatString(Places))
+	}
+	return Decimal(q.Int64()), nil
+}
+
+// Rat returns the exact value of d.
+func (d Decimal) Rat() *big.Rat {
+	return big.NewRat(int64(d), scale)
+}
+
+// Float64 returns the closest float to d.
+func (d Decimal) Float64() float64 {
+	return float64(d) / scale
+}
+
+// Add returns d + e.
+func (d Decimal) Add(e Decimal) Decimal { return d + e }
+
+// Sub returns d - e.
+func (d Decimal) Sub(e Decimal) Decimal { return d - e }
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal { return -d }
+
+// MulInt returns d times n, such as a unit price times a quantity.
+func (d Decimal) MulInt(n int) Decimal { return d * Decimal(n) }
+
+// Mul returns d times r rounded to 4 places.
+func (d Decimal) Mul(r *big.Rat) (Decimal, error) {
+	return FromRat(new(big.Rat).Mul(d.Rat(), r))
+}
+
+// Sign returns -1, 0 or 1 for negative, zero or positive d.
+func (d Decimal) Sign() int {
+	switch {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// Round rounds d half away from zero to the given number of places.
+func (d Decimal) Round(places int) Decimal {
+	if places >= Places {
+		return d
+	}
+	unit := Decimal(math.Pow10(Places - places))
+	half := unit / 2
+	if d < 0 {
+		return -((-d + half) / unit * unit)
+	}
+	return (d + half) / unit * unit
+}
+
+// String formats d with at least 2 and at most 4 decimal places.
+func (d Decimal) String() string {
+	return d.StringFixed(-1)
+}
+
+// StringFixed formats d with the given number of decimal places, rounding
+// as needed. A negative number of places trims zeros down to 2 places.
+func (d Decimal) StringFixed(places int) string {
+	if places > Places {
+		places = Places
+	}
+	if places >= 0 {
+		d = d.Round(places)
+	}
+	sign := ""
+	n := int64(d)
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	frac := fmt.Sprintf("%04d", n%scale)
+	if places >= 0 {
+		frac = frac[:places]
+	} else {
+		frac = strings.TrimRight(frac, "0")
+		for len(frac) < 2 {
+			frac += "0"
+		}
+	}
+	s := sign + strconv.FormatInt(n/scale, 10)
+	if frac != "" {
+		s += "." + frac
+	}
+	return s
+}
+
+// MarshalText formats d as a string with 4 decimal places.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.StringFixed(Places)), nil
+}
+
+// UnmarshalJSON parses a decimal given as a JSON string or number. An
+// empty string is zero and null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// UnmarshalText parses a decimal. Empty text is zero.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = 0
+		return nil
+	}
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
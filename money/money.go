@@ -0,0 +1,105 @@
+// Package money handles prices in multiple currencies with exact decimal
+// arithmetic.
+//
+// Amounts are fixed-point decimals with 4 places, as used by BrickLink.
+// Money in different currencies can only be combined after conversion with
+// a RateSource, such as the rates implied by BrickLink's native and display
+// prices or a user-supplied table.
+package money
+
+import "fmt"
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+// Money is an amount in a currency.
+type Money struct {
+	Amount   Decimal  `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// New returns an amount in a currency.
+func New(amount Decimal, currency Currency) Money {
+	return Money{amount, currency}
+}
+
+// MismatchError is returned when combining money in different currencies.
+type MismatchError struct {
+	A, B Currency
+}
+
+func (err *MismatchError) Error() string {
+	return fmt.Sprintf("money: currency mismatch %s and %s", err.A, err.B)
+}
+
+// Add returns m + n. Zero money without a currency takes the currency of
+// the other.
+func (m Money) Add(n Money) (Money, error) {
+	c, err := combine(m, n)
+	return Money{m.Amount + n.Amount, c}, err
+}
+
+// Sub returns m - n.
+func (m Money) Sub(n Money) (Money, error) {
+	c, err := combine(m, n)
+	return Money{m.Amount - n.Amount, c}, err
+}
+
+func combine(m, n Money) (Currency, error) {
+	switch {
+	case m.Currency == n.Currency, n.Currency == "" && n.Amount == 0:
+		return m.Currency, nil
+	case m.Currency == "" && m.Amount == 0:
+		return n.Currency, nil
+	}
+	return "", &MismatchError{m.Currency, n.Currency}
+}
+
+// MulInt returns m times n.
+func (m Money) MulInt(n int) Money {
+	return Money{m.Amount.MulInt(n), m.Currency}
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// String formats money as the currency code followed by the amount, such
+// as "USD 218.76".
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return string(m.Currency) + " " + m.Amount.String()
+}
+
+// Totals sums money by currency.
+type Totals map[Currency]Decimal
+
+// Add adds money to the total of its currency.
+func (t Totals) Add(m Money) {
+	t[m.Currency] += m.Amount
+}
+
+// Sum converts each total to one currency and sums them.
+func (t Totals) Sum(to Currency, rates RateSource) (Money, error) {
+	sum := Money{Currency: to}
+	for c, amount := range t {
+		m, err := Convert(Money{amount, c}, to, rates)
+		if err != nil {
+			return Money{}, err
+		}
+		sum.Amount += m.Amount
+	}
+	return sum, nil
+}
+
+// Sum converts money to one currency and sums it.
+func Sum(ms []Money, to Currency, rates RateSource) (Money, error) {
+	t := make(Totals)
+	for _, m := range ms {
+		t.Add(m)
+	}
+	return t.Sum(to, rates)
+}
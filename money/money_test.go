@@ -0,0 +1,180 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"1", "1.00"},
+		{"0.1", "0.10"},
+		{"-12.3456", "-12.3456"},
+		{"0.00005", "0.0001"},
+		{"-0.00005", "-0.0001"},
+		{"0.00004", "0.00"},
+		{"218.7600", "218.76"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): %v", tt.in, err)
+			continue
+		}
+		if s := d.String(); s != tt.out {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, s, tt.out)
+		}
+	}
+	for _, in := range []string{"", "1e3", "1/2", "0x10", "1.2.3", "-"} {
+		if _, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q): expected error", in)
+		}
+	}
+
+	// Summing 0.1 ten thousand times is exact.
+	var sum Decimal
+	for i := 0; i < 10000; i++ {
+		sum = sum.Add(MustParseDecimal("0.1"))
+	}
+	if sum != FromInt(1000) {
+		t.Errorf("sum = %s, want 1000", sum)
+	}
+	if s := MustParseDecimal("2.345").StringFixed(2); s != "2.35" {
+		t.Errorf("StringFixed(2) = %s, want 2.35", s)
+	}
+	if s := MustParseDecimal("-2.345").StringFixed(2); s != "-2.35" {
+		t.Errorf("StringFixed(2) = %s, want -2.35", s)
+	}
+
+	b, err := json.Marshal(struct{ D Decimal }{MustParseDecimal("1.5")})
+	if err != nil || string(b) != `{"D":"1.5000"}` {
+		t.Errorf("Marshal = %s, %v", b, err)
+	}
+	var v struct{ D Decimal }
+	if err := json.Unmarshal(b, &v); err != nil || v.D != MustParseDecimal("1.5") {
+		t.Errorf("Unmarshal = %v, %v", v.D, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in  string
+		out Money
+	}{
+		{"NOK 1,900.00", New(FromInt(1900), "NOK")},
+		{"US $218.76", New(MustParseDecimal("218.76"), "USD")},
+		{"~EUR 0.05", New(MustParseDecimal("0.05"), "EUR")},
+		{"CA $1.2345", New(MustParseDecimal("1.2345"), "CAD")},
+		{"US$3", New(FromInt(3), "USD")},
+		{"-GBP 2.50", New(MustParseDecimal("-2.5"), "GBP")},
+		{"12.00", New(FromInt(12), "")},
+		{"US $1,234,567.89", New(MustParseDecimal("1234567.89"), "USD")},
+	}
+	for _, tt := range tests {
+		m, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if m != tt.out {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, m, tt.out)
+		}
+	}
+	for _, in := range []string{"", "US $", "XX $1.00", "USD 1,2.3.4", "EUR 1.234,56", "EUR 1,23", "US $,100.00"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q): expected error", in)
+		}
+	}
+	if _, err := ParseIn("US $1.00", "EUR"); err == nil {
+		t.Error("ParseIn: expected currency mismatch")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	table := NewTable()
+	if err := table.Set("USD", "EUR", "0.9"); err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Set("GBP", "USD", "1.25"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in   Money
+		to   Currency
+		want string
+	}{
+		{New(FromInt(10), "USD"), "EUR", "9.00"},
+		{New(FromInt(9), "EUR"), "USD", "10.00"},
+		{New(FromInt(10), "GBP"), "EUR", "11.25"},
+		{New(FromInt(1), "EUR"), "USD", "1.1111"},
+	}
+	for _, tt := range tests {
+		m, err := Convert(tt.in, tt.to, table)
+		if err != nil {
+			t.Errorf("Convert(%v, %s): %v", tt.in, tt.to, err)
+			continue
+		}
+		if m.Currency != tt.to || m.Amount.String() != tt.want {
+			t.Errorf("Convert(%v, %s) = %v, want %s", tt.in, tt.to, m, tt.want)
+		}
+	}
+	if _, err := Convert(New(FromInt(1), "NOK"), "USD", table); err == nil {
+		t.Error("expected missing rate")
+	}
+
+	implied := NewImpliedRates()
+	implied.Observe(New(FromInt(1900), "NOK"), New(MustParseDecimal("180"), "USD"))
+	implied.Observe(New(MustParseDecimal("20"), "USD"), New(FromInt(200), "NOK"))
+	rate, err := implied.Rate("NOK", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Cmp(big.NewRat(200, 2100)) != 0 {
+		t.Errorf("implied rate = %s, want 2/21", rate.RatString())
+	}
+
+	var totals = make(Totals)
+	totals.Add(New(FromInt(210), "NOK"))
+	totals.Add(New(FromInt(5), "USD"))
+	totals.Add(New(FromInt(10), "GBP"))
+	sum, err := totals.Sum("USD", Sources{implied, table})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.String() != "USD 37.50" {
+		t.Errorf("sum = %v, want USD 37.50", sum)
+	}
+	if _, err := New(FromInt(1), "USD").Add(New(FromInt(1), "EUR")); err == nil {
+		t.Error("expected currency mismatch")
+	}
+}
+
+func TestDeriveOrder(t *testing.T) {
+	table := NewTable()
+	for _, r := range []struct {
+		from, to Currency
+		rate     string
+	}{
+		// GBP to JPY through USD is 200 and through CHF 150.
+		{"GBP", "USD", "1.25"}, {"USD", "JPY", "160"},
+		{"GBP", "CHF", "1.5"}, {"CHF", "JPY", "100"},
+		// NOK to SEK through DKK is 1.5 and through CHF 1.
+		{"NOK", "DKK", "0.75"}, {"DKK", "SEK", "2"},
+		{"NOK", "CHF", "0.1"}, {"CHF", "SEK", "10"},
+	} {
+		if err := table.Set(r.from, r.to, r.rate); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		if rate, err := table.Rate("GBP", "JPY"); err != nil || rate.Cmp(big.NewRat(200, 1)) != 0 {
+			t.Fatalf("GBP to JPY = %v, %v, want 200 through USD", rate, err)
+		}
+		if rate, err := table.Rate("NOK", "SEK"); err != nil || rate.Cmp(big.NewRat(1, 1)) != 0 {
+			t.Fatalf("NOK to SEK = %v, %v, want 1 through CHF", rate, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// symbols are the currency prefixes BrickLink uses other than ISO codes.
+var symbols = map[string]Currency{
+	"US $": "USD",
+	"CA $": "CAD",
+	"AU $": "AUD",
+	"NZ $": "NZD",
+	"HK $": "HKD",
+	"SG $": "SGD",
+	"MX $": "MXN",
+	"£":    "GBP",
+	"€":    "EUR",
+}
+
+// Parse parses a price formatted by BrickLink, such as "US $218.76",
+// "NOK 1,900.00" or the approximate "~EUR 0.05". The currency is empty
+// when the price has no prefix.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "~"))
+	neg := false
+	if strings.HasPrefix(s, "-") {
+		neg = true
+		s = strings.TrimSpace(s[1:])
+	}
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.' || r == '-'
+	})
+	if i == -1 {
+		return Money{}, fmt.Errorf("money: invalid price %q", s)
+	}
+	prefix, number := strings.TrimSpace(s[:i]), s[i:]
+	var currency Currency
+	if prefix != "" {
+		c, ok := symbols[strings.ToUpper(prefix)]
+		if !ok {
+			// Also accept "US$" without a space.
+			c, ok = symbols[strings.ToUpper(strings.Replace(prefix, "$", " $", 1))]
+		}
+		if !ok && isCode(prefix) {
+			c, ok = Currency(strings.ToUpper(prefix)), true
+		}
+		if !ok {
+			return Money{}, fmt.Errorf("money: unknown currency %q in %q", prefix, s)
+		}
+		currency = c
+	}
+	number, ok := ungroup(number)
+	if !ok {
+		return Money{}, fmt.Errorf("money: ambiguous digit grouping in %q", s)
+	}
+	amount, err := ParseDecimal(number)
+	if err != nil {
+		return Money{}, fmt.Errorf("money: invalid price %q", s)
+	}
+	if neg {
+		amount = -amount
+	}
+	return Money{amount, currency}, nil
+}
+
+// ParseIn parses a price that may omit its currency, such as a
+// bricklinkuser native price, which is in the currency of the store.
+func ParseIn(s string, currency Currency) (Money, error) {
+	m, err := Parse(s)
+	if err != nil {
+		return Money{}, err
+	}
+	if m.Currency == "" {
+		m.Currency = currency
+	} else if m.Currency != currency {
+		return Money{}, &MismatchError{m.Currency, currency}
+	}
+	return m, nil
+}
+
+// ungroup removes the thousands separators from a number such as
+// "1,234.56". It reports false when commas are not between groups of three
+// digits before the decimal point, as in the locale-formatted "1.234,56",
+// rather than guessing which separator is decimal.
+func ungroup(number string) (string, bool) {
+	integer, fraction := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		integer, fraction = number[:i], number[i:]
+	}
+	if strings.Contains(fraction, ",") {
+		return "", false
+	}
+	if !strings.Contains(integer, ",") {
+		return number, true
+	}
+	groups := strings.Split(integer, ",")
+	for i, g := range groups {
+		if (i == 0 && (len(g) == 0 || len(g) > 3)) || (i != 0 && len(g) != 3) {
+			return "", false
+		}
+	}
+	return strings.Join(groups, "") + fraction, true
+}
+
+func isCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) || r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
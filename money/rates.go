@@ -0,0 +1,204 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// RateSource provides exchange rates. A rate is the amount of to that one
+// unit of from buys.
+type RateSource interface {
+	Rate(from, to Currency) (*big.Rat, error)
+}
+
+// NoRateError is returned when a source has no rate between currencies.
+type NoRateError struct {
+	From, To Currency
+}
+
+func (err *NoRateError) Error() string {
+	return fmt.Sprintf("money: no rate from %s to %s", err.From, err.To)
+}
+
+// Convert converts money to another currency, rounding to 4 places.
+func Convert(m Money, to Currency, rates RateSource) (Money, error) {
+	if m.Currency == to || m.Amount == 0 {
+		return Money{m.Amount, to}, nil
+	}
+	if rates == nil {
+		return Money{}, &NoRateError{m.Currency, to}
+	}
+	rate, err := rates.Rate(m.Currency, to)
+	if err != nil {
+		return Money{}, err
+	}
+	amount, err := m.Amount.Mul(rate)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount, to}, nil
+}
+
+type pair struct {
+	from, to Currency
+}
+
+// Table is a user-supplied table of rates. Inverse rates and rates through
+// one intermediate currency are derived as needed.
+type Table struct {
+	mu    sync.Mutex
+	rates map[pair]*big.Rat
+}
+
+// NewTable creates an empty table.
+func NewTable() *Table {
+	return &Table{rates: make(map[pair]*big.Rat)}
+}
+
+// Set sets the rate from one currency to another as a decimal string such
+// as "0.0934", which is kept exactly.
+func (t *Table) Set(from, to Currency, rate string) error {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return fmt.Errorf("money: invalid rate %q", rate)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rates[pair{from, to}] = r
+	return nil
+}
+
+// Rate returns the rate from one currency to another.
+func (t *Table) Rate(from, to Currency) (*big.Rat, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return derive(t.rates, from, to)
+}
+
+// derive finds a rate directly, inverted, or through one intermediate
+// currency, trying USD, then EUR, then the others in sorted order.
+func derive(rates map[pair]*big.Rat, from, to Currency) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	direct := func(from, to Currency) *big.Rat {
+		if r, ok := rates[pair{from, to}]; ok {
+			return new(big.Rat).Set(r)
+		}
+		if r, ok := rates[pair{to, from}]; ok {
+			return new(big.Rat).Inv(r)
+		}
+		return nil
+	}
+	if r := direct(from, to); r != nil {
+		return r, nil
+	}
+	for _, via := range intermediates(rates, from, to) {
+		if a, b := direct(from, via), direct(via, to); a != nil && b != nil {
+			return a.Mul(a, b), nil
+		}
+	}
+	return nil, &NoRateError{from, to}
+}
+
+// preferredIntermediates are tried first, in order, when deriving a rate
+// through another currency.
+var preferredIntermediates = []Currency{"USD", "EUR"}
+
+// intermediates returns the currencies in rates other than from and to,
+// with the preferred intermediates first and the rest in sorted order, so
+// that derived rates do not depend on map order.
+func intermediates(rates map[pair]*big.Rat, from, to Currency) []Currency {
+	seen := map[Currency]bool{from: true, to: true}
+	var vias []Currency
+	for p := range rates {
+		for _, c := range []Currency{p.from, p.to} {
+			if !seen[c] {
+				seen[c] = true
+				vias = append(vias, c)
+			}
+		}
+	}
+	rank := func(c Currency) int {
+		for i, p := range preferredIntermediates {
+			if c == p {
+				return i
+			}
+		}
+		return len(preferredIntermediates)
+	}
+	sort.Slice(vias, func(i, j int) bool {
+		if ri, rj := rank(vias[i]), rank(vias[j]); ri != rj {
+			return ri < rj
+		}
+		return vias[i] < vias[j]
+	})
+	return vias
+}
+
+// ImpliedRates derives rates from prices given in two currencies, such as
+// the native and display costs of BrickLink orders. The rate between a
+// pair of currencies is the ratio of the sums of all observations, so that
+// rounding in individual prices averages out.
+type ImpliedRates struct {
+	mu   sync.Mutex
+	sums map[pair][2]*big.Rat
+}
+
+// NewImpliedRates creates an empty set of implied rates.
+func NewImpliedRates() *ImpliedRates {
+	return &ImpliedRates{sums: make(map[pair][2]*big.Rat)}
+}
+
+// Observe records that from and to are the same price in two currencies.
+// Observations with a zero amount or in the same currency are ignored.
+func (ir *ImpliedRates) Observe(from, to Money) {
+	if from.Currency == to.Currency || from.Currency == "" || to.Currency == "" ||
+		from.Amount.Sign() <= 0 || to.Amount.Sign() <= 0 {
+		return
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	// Observations in either direction share the sums of one pair.
+	p := pair{from.Currency, to.Currency}
+	if _, ok := ir.sums[pair{to.Currency, from.Currency}]; ok {
+		p, from, to = pair{to.Currency, from.Currency}, to, from
+	}
+	sums, ok := ir.sums[p]
+	if !ok {
+		sums = [2]*big.Rat{new(big.Rat), new(big.Rat)}
+		ir.sums[p] = sums
+	}
+	sums[0].Add(sums[0], from.Amount.Rat())
+	sums[1].Add(sums[1], to.Amount.Rat())
+}
+
+// Rate returns the implied rate from one currency to another.
+func (ir *ImpliedRates) Rate(from, to Currency) (*big.Rat, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	rates := make(map[pair]*big.Rat, len(ir.sums))
+	for p, sums := range ir.sums {
+		rates[p] = new(big.Rat).Quo(sums[1], sums[0])
+	}
+	return derive(rates, from, to)
+}
+
+// Sources tries each source in order and returns the first rate found.
+type Sources []RateSource
+
+// Rate returns the first rate found.
+func (s Sources) Rate(from, to Currency) (*big.Rat, error) {
+	for _, source := range s {
+		r, err := source.Rate(from, to)
+		if err == nil {
+			return r, nil
+		}
+		if _, ok := err.(*NoRateError); !ok {
+			return nil, err
+		}
+	}
+	return nil, &NoRateError{from, to}
+}
@@ -0,0 +1,202 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/andrewarchi/brick-apis/bricklinkuser"
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+// searchPageSize is the number of results requested per page of
+// SearchProduct.
+const searchPageSize = 100
+
+// Search builds a problem by searching the lots for sale for each want,
+// reading every page of results. Results for other items, which a search
+// by item number may also match, are discarded. Results that do not
+// identify their item are taken to be the item searched for.
+//
+// Amounts are in the user's display currency. Store minimum buys are
+// converted from the store currency with the rates implied by the store
+// and display prices of the lots, which are kept in the problem's Rates
+// for SetShipping. Shipping is unknown until set with SetShipping and
+// seller IDs until set with SetSellerIDs.
+func Search(c *bricklinkuser.Client, wants []Want) (*Problem, error) {
+	rates := money.NewImpliedRates()
+	p := &Problem{Wants: wants, Stores: make(map[string]Store), Rates: rates}
+	minBuys := make(map[string]money.Money)
+	for _, w := range wants {
+		for page := 1; ; page++ {
+			options := &bricklinkuser.SearchProductOptions{
+				Query:          w.ItemNumber,
+				ItemType:       w.ItemType,
+				Condition:      w.Condition,
+				ColorID:        w.ColorID,
+				NoSuperLots:    true,
+				ResultsPerPage: searchPageSize,
+				Page:           page,
+			}
+			r, err := c.SearchProduct(options)
+			if err != nil {
+				return nil, fmt.Errorf("planner: search %s %s: %v", w.ItemType, w.ItemNumber, err)
+			}
+			for _, item := range r.List {
+				if !resultMatches(item, w) {
+					continue
+				}
+				lot, currency, err := LotFromProduct(item, rates)
+				if err != nil {
+					return nil, err
+				}
+				if p.Currency == "" {
+					p.Currency = currency
+				} else if currency != p.Currency {
+					return nil, &money.MismatchError{A: currency, B: p.Currency}
+				}
+				// Match the want regardless of case or missing item keys.
+				lot.ItemType, lot.ItemNumber = w.ItemType, w.ItemNumber
+				p.Lots = append(p.Lots, lot)
+				if _, ok := p.Stores[item.SellerUsername]; !ok {
+					store := Store{Username: item.SellerUsername, Currency: storeCurrency(item)}
+					if strings.TrimSpace(item.MinBuy) != "" {
+						minBuy, err := parseStorePrice(item.MinBuy, store.Currency)
+						if err != nil {
+							return nil, err
+						}
+						store.Currency = minBuy.Currency
+						minBuys[item.SellerUsername] = minBuy
+					}
+					p.Stores[item.SellerUsername] = store
+				}
+			}
+			if len(r.List) == 0 || page*searchPageSize >= r.TotalCount {
+				break
+			}
+		}
+	}
+	// Convert minimum buys once every lot has contributed to the rates.
+	for username, minBuy := range minBuys {
+		m, err := money.Convert(minBuy, p.Currency, p.Rates)
+		if err != nil {
+			return nil, fmt.Errorf("planner: minimum buy of %s: %v", username, err)
+		}
+		store := p.Stores[username]
+		store.MinBuy = m.Amount
+		p.Stores[username] = store
+	}
+	return p, nil
+}
+
+// resultMatches reports whether a search result is for the wanted item.
+// The item of a result is not always given, in which case only the item
+// type and color filters of the search apply.
+func resultMatches(item bricklinkuser.ProductList, w Want) bool {
+	if item.ItemType != "" && item.ItemType != w.ItemType {
+		return false
+	}
+	return item.ItemNumber == "" || strings.EqualFold(item.ItemNumber, w.ItemNumber)
+}
+
+// LotFromProduct converts a search result into a lot priced in the user's
+// display currency, which is returned. Each price is also observed in
+// rates with its store currency equivalent, when rates is not nil.
+func LotFromProduct(item bricklinkuser.ProductList, rates *money.ImpliedRates) (Lot, money.Currency, error) {
+	lot := Lot{
+		InvID:          item.InvID,
+		SellerUsername: item.SellerUsername,
+		StoreName:      item.StoreName,
+		ItemType:       item.ItemType,
+		ItemNumber:     item.ItemNumber,
+		ColorID:        item.ColorID,
+		Condition:      item.NewOrUsed,
+		Quantity:       item.Quantity,
+	}
+	currency := storeCurrency(item)
+	prices := []struct {
+		qty            int
+		store, display string
+	}{
+		{0, item.SalePrice, item.SalePriceDisplay},
+		{item.Tier1Quantity, item.Tier1Price, item.Tier1PriceDisplay},
+		{item.Tier2Quantity, item.Tier2Price, item.Tier2PriceDisplay},
+		{item.Tier3Quantity, item.Tier3Price, item.Tier3PriceDisplay},
+	}
+	var display money.Currency
+	for i, p := range prices {
+		if i != 0 && p.qty <= 0 {
+			continue
+		}
+		d, err := money.Parse(p.display)
+		if err != nil {
+			return Lot{}, "", err
+		}
+		if d.Currency == "" {
+			return Lot{}, "", fmt.Errorf("planner: display price %q has no currency", p.display)
+		}
+		if display == "" {
+			display = d.Currency
+		} else if d.Currency != display {
+			return Lot{}, "", &money.MismatchError{A: d.Currency, B: display}
+		}
+		if rates != nil && strings.TrimSpace(p.store) != "" {
+			s, err := parseStorePrice(p.store, currency)
+			if err != nil {
+				return Lot{}, "", err
+			}
+			rates.Observe(s, d)
+		}
+		if i == 0 {
+			lot.Price = d.Amount
+		} else {
+			lot.Tiers = append(lot.Tiers, Tier{p.qty, d.Amount})
+		}
+	}
+	return lot, display, nil
+}
+
+// SetShipping sets the estimated shipping of a store from its checkout
+// conditions, converting it from the store currency with the problem's
+// rates.
+func (p *Problem) SetShipping(username string, conditions bricklinkuser.Conditions) error {
+	store, ok := p.Stores[username]
+	if !ok {
+		store = Store{Username: username}
+	}
+	native, err := parseStorePrice(conditions.EstimatedShippingAndHandlingNative, store.Currency)
+	if err != nil {
+		return err
+	}
+	shipping, err := money.Convert(native, p.Currency, p.Rates)
+	if err != nil {
+		return fmt.Errorf("planner: shipping of %s: %v", username, err)
+	}
+	store.Shipping = shipping.Amount
+	p.Stores[username] = store
+	return nil
+}
+
+// storeCurrency returns the currency of the store of a search result, or
+// "" when it is unknown.
+func storeCurrency(item bricklinkuser.ProductList) money.Currency {
+	if c := bricklinkuser.GetCurrencyByID(item.StoreCurrencyID); c != nil {
+		return money.Currency(c.CurrencyCode)
+	}
+	return ""
+}
+
+// parseStorePrice parses a price in the currency of a store, which the
+// price may omit.
+func parseStorePrice(s string, currency money.Currency) (money.Money, error) {
+	if currency != "" {
+		return money.ParseIn(s, currency)
+	}
+	m, err := money.Parse(s)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if m.Currency == "" {
+		return money.Money{}, fmt.Errorf("planner: store price %q has no currency", s)
+	}
+	return m, nil
+}
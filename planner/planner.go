@@ -0,0 +1,241 @@
+// Package planner chooses the stores and lots to buy wanted items from at
+// low total cost.
+//
+// The cost of a plan is the tiered price of each lot bought, the estimated
+// shipping of each store used and, for stores where the order falls short
+// of the minimum buy, the amount needed to reach it. All amounts are in
+// the problem's currency, such as the user's display currency on
+// BrickLink.
+//
+// BranchAndBound finds the cheapest plan exactly by searching the quantity
+// bought from each lot, honouring tier prices and minimum buys, but takes
+// time exponential in the number of lots. Greedy is a heuristic for large
+// problems. Neither buys more of a lot than is wanted, even when a higher
+// tier would cost less.
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/andrewarchi/brick-apis/bricklinkuser"
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+// Want is a quantity of an item to buy.
+type Want struct {
+	ItemType   bricklinkuser.ItemType
+	ItemNumber string
+	ColorID    int
+	Condition  bricklinkuser.NewOrUsed // "" for either
+	Quantity   int
+}
+
+// Lot is an item for sale in a store.
+type Lot struct {
+	InvID          int
+	SellerUsername string
+	StoreName      string
+	ItemType       bricklinkuser.ItemType
+	ItemNumber     string
+	ColorID        int
+	Condition      bricklinkuser.NewOrUsed
+	Quantity       int
+	Price          money.Decimal // Unit price below the first tier
+	Tiers          []Tier        // Quantity discounts in increasing order of quantity
+}
+
+// Tier is a unit price for buying at least Quantity of a lot.
+type Tier struct {
+	Quantity int
+	Price    money.Decimal
+}
+
+// UnitPrice returns the unit price when buying qty of the lot.
+func (l *Lot) UnitPrice(qty int) money.Decimal {
+	price := l.Price
+	for _, t := range l.Tiers {
+		if t.Quantity > 0 && qty >= t.Quantity {
+			price = t.Price
+		}
+	}
+	return price
+}
+
+// Matches reports whether the lot can fill a want.
+func (l *Lot) Matches(w Want) bool {
+	return l.ItemType == w.ItemType && l.ItemNumber == w.ItemNumber && l.ColorID == w.ColorID &&
+		(w.Condition == "" || l.Condition == w.Condition)
+}
+
+// Store contains the costs of ordering from a store.
+type Store struct {
+	Username string
+	SellerID int            // Needed by AddToCart, but not included in SearchProduct results
+	Currency money.Currency // Currency of the store, in which shipping is quoted
+	Shipping money.Decimal  // Estimated shipping and handling
+	MinBuy   money.Decimal  // Minimum order subtotal
+}
+
+// Problem is a set of wants and the lots and stores to fill them from.
+type Problem struct {
+	Wants    []Want
+	Lots     []Lot
+	Stores   map[string]Store // By seller username; stores not listed have no costs
+	Currency money.Currency   // Currency of all amounts
+	Rates    money.RateSource // Converts store amounts into Currency
+}
+
+// SetSellerIDs sets the seller IDs of the stores in a cart, such as from
+// GetGlobalCart. SearchProduct results do not include seller IDs, so they
+// must be set before adding a plan to the cart.
+func (p *Problem) SetSellerIDs(cart *bricklinkuser.CartInfo) error {
+	for _, s := range cart.List {
+		id, err := strconv.Atoi(s.SellerID)
+		if err != nil {
+			return fmt.Errorf("planner: invalid seller ID %q for %s", s.SellerID, s.Username)
+		}
+		p.SetSellerID(s.Username, id)
+	}
+	return nil
+}
+
+// SetSellerID sets the seller ID of a store.
+func (p *Problem) SetSellerID(username string, sellerID int) {
+	store, ok := p.Stores[username]
+	if !ok {
+		store = Store{Username: username}
+	}
+	store.SellerID = sellerID
+	p.Stores[username] = store
+}
+
+// Plan is the result of solving a problem.
+type Plan struct {
+	Stores   []StorePlan
+	Total    money.Decimal // Cost of all stores
+	Currency money.Currency
+	Missing  []Want // Quantities that no store could fill
+}
+
+// StorePlan is the order to place with a store.
+type StorePlan struct {
+	Store
+	StoreName       string
+	Purchases       []Purchase
+	Subtotal        money.Decimal // Cost of the purchases
+	MinBuyShortfall money.Decimal // Amount needed to reach the minimum buy
+}
+
+// Total returns the subtotal, shipping and minimum buy shortfall.
+func (s *StorePlan) Total() money.Decimal {
+	return s.Subtotal.Add(s.Shipping).Add(s.MinBuyShortfall)
+}
+
+// Purchase is a quantity bought from a lot.
+type Purchase struct {
+	Lot       Lot
+	Want      int // Index in Problem.Wants
+	Quantity  int
+	UnitPrice money.Decimal
+}
+
+// Cost returns the cost of the purchase.
+func (p *Purchase) Cost() money.Decimal {
+	return p.UnitPrice.MulInt(p.Quantity)
+}
+
+// SID returns the seller ID in the form taken by AddToCart.
+func (s *StorePlan) SID() string {
+	return strconv.Itoa(s.SellerID)
+}
+
+// CartItems returns the purchases in the form taken by AddToCart. It
+// returns an error when the seller ID of the store is unknown.
+func (s *StorePlan) CartItems() ([]bricklinkuser.CartItemSimple, error) {
+	if s.SellerID == 0 {
+		return nil, fmt.Errorf("planner: unknown seller ID for %s; set it with SetSellerIDs", s.Username)
+	}
+	items := make([]bricklinkuser.CartItemSimple, len(s.Purchases))
+	for i, p := range s.Purchases {
+		items[i] = bricklinkuser.CartItemSimple{
+			ID:       p.Lot.InvID,
+			Quantity: strconv.Itoa(p.Quantity),
+			SellerID: s.SellerID,
+		}
+	}
+	return items, nil
+}
+
+// Mode selects the algorithm used by Solve.
+type Mode int
+
+// Solving modes.
+const (
+	Auto           Mode = iota // BranchAndBound limited to MaxBranchNodes nodes
+	BranchAndBound             // Search the quantity bought from each lot for the cheapest plan
+	Greedy                     // Add the most cost effective store until all wants are filled
+)
+
+// MaxBranchNodes is the number of nodes searched with branch and bound in
+// Auto mode, after which the best plan found so far, which is no worse than
+// the greedy plan, is used.
+const MaxBranchNodes = 1 << 20
+
+// Solve finds a plan that fills as much of the wants as the lots allow at
+// low cost. In BranchAndBound mode, and in Auto mode when the search
+// finishes within MaxBranchNodes nodes, the plan is the cheapest.
+func Solve(p *Problem, mode Mode) (*Plan, error) {
+	s := newSolver(p)
+	var r *result
+	switch mode {
+	case Auto:
+		r, _ = s.branchAndBound(MaxBranchNodes)
+	case BranchAndBound:
+		r, _ = s.branchAndBound(0)
+	case Greedy:
+		r = s.greedy()
+	default:
+		return nil, fmt.Errorf("planner: unknown mode %d", mode)
+	}
+	return s.plan(r), nil
+}
+
+func (s *solver) plan(r *result) *Plan {
+	plan := &Plan{Currency: s.p.Currency}
+	byStore := make(map[int]*StorePlan)
+	var order []int
+	for _, pur := range r.purchases {
+		si := s.lotStore[pur.lot]
+		sp, ok := byStore[si]
+		if !ok {
+			sp = &StorePlan{Store: s.stores[si]}
+			byStore[si] = sp
+			order = append(order, si)
+		}
+		lot := s.p.Lots[pur.lot]
+		if lot.StoreName != "" {
+			sp.StoreName = lot.StoreName
+		}
+		sp.Purchases = append(sp.Purchases, Purchase{lot, pur.want, pur.qty, pur.unit})
+		sp.Subtotal = sp.Subtotal.Add(pur.unit.MulInt(pur.qty))
+	}
+	sort.Ints(order)
+	for _, si := range order {
+		sp := byStore[si]
+		if sp.Subtotal < sp.MinBuy {
+			sp.MinBuyShortfall = sp.MinBuy.Sub(sp.Subtotal)
+		}
+		plan.Stores = append(plan.Stores, *sp)
+		plan.Total = plan.Total.Add(sp.Total())
+	}
+	for i, missing := range r.missingByWant {
+		if missing > 0 {
+			w := s.p.Wants[i]
+			w.Quantity = missing
+			plan.Missing = append(plan.Missing, w)
+		}
+	}
+	return plan
+}
@@ -0,0 +1,198 @@
+package planner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/andrewarchi/brick-apis/bricklinkuser"
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+func part(no string, colorID, qty int) Want {
+	return Want{ItemType: bricklinkuser.ItemTypePart, ItemNumber: no, ColorID: colorID, Quantity: qty}
+}
+
+func lot(invID int, seller string, no string, colorID, qty int, price string, tiers ...Tier) Lot {
+	return Lot{InvID: invID, SellerUsername: seller, ItemType: bricklinkuser.ItemTypePart, ItemNumber: no,
+		ColorID: colorID, Condition: bricklinkuser.N, Quantity: qty, Price: money.MustParseDecimal(price), Tiers: tiers}
+}
+
+func d(s string) money.Decimal {
+	return money.MustParseDecimal(s)
+}
+
+func testProblem() *Problem {
+	return &Problem{
+		Wants: []Want{part("3024", 11, 100), part("3001", 5, 10), part("3004", 1, 4)},
+		Lots: []Lot{
+			// a is cheap for plates but charges high shipping.
+			lot(1, "a", "3024", 11, 100, "0.02"),
+			lot(2, "a", "3001", 5, 10, "0.20"),
+			// bb has tiered plates and both other parts.
+			lot(3, "bb", "3024", 11, 60, "0.05", Tier{50, d("0.03")}),
+			lot(4, "bb", "3001", 5, 10, "0.15"),
+			lot(5, "bb", "3004", 1, 2, "0.10"),
+			// ccc fills the rest of the plates and bricks.
+			lot(6, "ccc", "3024", 11, 40, "0.04"),
+			lot(7, "ccc", "3004", 1, 4, "0.12"),
+			lot(8, "ccc", "3001", 1, 10, "0.01"), // Wrong color
+		},
+		Stores: map[string]Store{
+			"a":   {Username: "a", Shipping: d("5")},
+			"bb":  {Username: "bb", Shipping: d("2"), MinBuy: d("5")},
+			"ccc": {Username: "ccc", Shipping: d("1")},
+		},
+		Currency: "USD",
+	}
+}
+
+func TestSolve(t *testing.T) {
+	for _, mode := range []Mode{BranchAndBound, Greedy} {
+		p := testProblem()
+		err := p.SetSellerIDs(&bricklinkuser.CartInfo{List: []bricklinkuser.StoreList{{SellerID: "2", Username: "bb"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan, err := Solve(p, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Missing) != 0 {
+			t.Errorf("mode %d: unexpected missing %+v", mode, plan.Missing)
+		}
+		// bb and ccc: 60*0.03 + 10*0.15 + 2*0.10 + 40*0.04 + 2*0.12 = 5.34,
+		// shipping 3, shortfall of bb's 5.00 minimum buy 1.50.
+		if want := d("9.84"); plan.Total != want || plan.Currency != "USD" {
+			t.Errorf("mode %d: expected total %s, but got %s %s: %+v", mode, want, plan.Currency, plan.Total, plan.Stores)
+		}
+		if len(plan.Stores) != 2 || plan.Stores[0].Username != "bb" || plan.Stores[1].Username != "ccc" {
+			t.Fatalf("mode %d: unexpected stores %+v", mode, plan.Stores)
+		}
+		items, err := plan.Stores[0].CartItems()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 3 || items[0] != (bricklinkuser.CartItemSimple{ID: 3, Quantity: "60", SellerID: 2}) {
+			t.Errorf("mode %d: unexpected cart items %+v", mode, items)
+		}
+		if _, err := plan.Stores[1].CartItems(); err == nil {
+			t.Errorf("mode %d: expected unknown seller ID for ccc", mode)
+		}
+	}
+}
+
+func TestSolveMissing(t *testing.T) {
+	p := testProblem()
+	p.Wants = append(p.Wants, part("3005", 1, 3))
+	p.Lots = append(p.Lots, lot(9, "ccc", "3005", 1, 1, "0.5"))
+	plan, err := Solve(p, Auto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Missing) != 1 || plan.Missing[0].ItemNumber != "3005" || plan.Missing[0].Quantity != 2 {
+		t.Errorf("unexpected missing %+v", plan.Missing)
+	}
+}
+
+func TestSolveExact(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     *Problem
+		total money.Decimal
+	}{
+		// Filling from the lowest unit price takes the 50 cheap plates and
+		// misses the tier of the larger lot.
+		{"tiers", &Problem{
+			Wants: []Want{part("3024", 11, 100)},
+			Lots: []Lot{
+				lot(1, "a", "3024", 11, 100, "0.05", Tier{100, d("0.03")}),
+				lot(2, "a", "3024", 11, 50, "0.02"),
+			},
+		}, d("3.00")},
+		// Moving 5 bricks to bb reaches its minimum buy for less than the
+		// shortfall.
+		{"minimum buy", &Problem{
+			Wants: []Want{part("3001", 5, 10), part("3004", 1, 5), part("3005", 1, 1)},
+			Lots: []Lot{
+				lot(1, "a", "3001", 5, 10, "0.08"),
+				lot(2, "a", "3005", 1, 1, "0.10"),
+				lot(3, "bb", "3001", 5, 10, "0.10"),
+				lot(4, "bb", "3004", 1, 5, "0.10"),
+			},
+			Stores: map[string]Store{
+				"a":  {Username: "a", Shipping: d("1")},
+				"bb": {Username: "bb", Shipping: d("1"), MinBuy: d("1")},
+			},
+		}, d("3.50")},
+	}
+	for _, test := range tests {
+		if test.p.Stores == nil {
+			test.p.Stores = make(map[string]Store)
+		}
+		for _, mode := range []Mode{BranchAndBound, Auto} {
+			plan, err := Solve(test.p, mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Missing) != 0 || plan.Total != test.total {
+				t.Errorf("%s, mode %d: expected total %s, but got %s: %+v", test.name, mode, test.total, plan.Total, plan.Stores)
+			}
+		}
+	}
+}
+
+func TestResultMatches(t *testing.T) {
+	w := part("3024", 11, 10)
+	for _, test := range []struct {
+		json    string
+		matches bool
+	}{
+		{`{"idInv":1,"typeItem":"P","strItemNo":"3024","idColor":11}`, true},
+		{`{"idInv":2,"typeItem":"P","strItemNo":"3024B","idColor":11}`, false},
+		{`{"idInv":3,"typeItem":"S","strItemNo":"3024","idColor":11}`, false},
+		{`{"idInv":4,"idColor":11,"strDesc":"","codeNew":"N","n4Qty":10}`, true},
+	} {
+		var item bricklinkuser.ProductList
+		if err := json.Unmarshal([]byte(test.json), &item); err != nil {
+			t.Fatal(err)
+		}
+		if got := resultMatches(item, w); got != test.matches {
+			t.Errorf("%s: expected %t, but got %t", test.json, test.matches, got)
+		}
+	}
+}
+
+func TestLotFromProduct(t *testing.T) {
+	rates := money.NewImpliedRates()
+	l, currency, err := LotFromProduct(bricklinkuser.ProductList{
+		InvID: 42, ItemType: bricklinkuser.ItemTypePart, ItemNumber: "3024", SellerUsername: "a", ColorID: 11,
+		NewOrUsed: bricklinkuser.U, Quantity: 500, StoreCurrencyID: 2,
+		SalePrice: "EUR 0.04", SalePriceDisplay: "US $0.05",
+		Tier1Quantity: 100, Tier1Price: "EUR 0.032", Tier1PriceDisplay: "US $0.04",
+		Tier2Quantity: 1000, Tier2Price: "EUR 800.00", Tier2PriceDisplay: "US $1,000.00",
+	}, rates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if currency != "USD" {
+		t.Errorf("expected display currency USD, but got %q", currency)
+	}
+	if l.Price != d("0.05") || len(l.Tiers) != 2 || l.Tiers[1].Price != d("1000") || l.UnitPrice(150) != d("0.04") || !l.Matches(part("3024", 11, 10)) {
+		t.Errorf("unexpected lot %+v", l)
+	}
+
+	p := &Problem{Stores: map[string]Store{"a": {Username: "a", Currency: "EUR"}}, Currency: currency, Rates: rates}
+	if err := p.SetShipping("a", bricklinkuser.Conditions{EstimatedShippingAndHandlingNative: "EUR 4.00"}); err != nil {
+		t.Fatal(err)
+	}
+	if s := p.Stores["a"].Shipping; s != d("5") {
+		t.Errorf("expected shipping converted to 5.00, but got %s", s)
+	}
+	if err := p.SetShipping("a", bricklinkuser.Conditions{EstimatedShippingAndHandlingNative: "GBP 4.00"}); err == nil {
+		t.Error("expected currency mismatch")
+	}
+
+	if _, _, err := LotFromProduct(bricklinkuser.ProductList{SalePriceDisplay: "EUR 1.234,56"}, nil); err == nil {
+		t.Error("expected ambiguous price to be rejected")
+	}
+}
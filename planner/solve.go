@@ -0,0 +1,381 @@
+package planner
+
+import (
+	"sort"
+
+	"github.com/andrewarchi/brick-apis/money"
+)
+
+type solver struct {
+	p          *Problem
+	stores     []Store
+	lotStore   []int   // Store index of each lot
+	lotsByWant [][]int // Indices of the lots matching each want
+}
+
+type purchase struct {
+	lot, want, qty int
+	unit           money.Decimal
+}
+
+type result struct {
+	cost          money.Decimal // Cost of the items, shipping and minimum buy shortfalls
+	items         money.Decimal // Cost of the items alone
+	missing       int
+	missingByWant []int
+	purchases     []purchase
+}
+
+// better reports whether r fills more of the wants than o, or as much for
+// less.
+func (r *result) better(o *result) bool {
+	if o == nil {
+		return true
+	}
+	if r.missing != o.missing {
+		return r.missing < o.missing
+	}
+	return r.cost < o.cost
+}
+
+func newSolver(p *Problem) *solver {
+	s := &solver{p: p, lotStore: make([]int, len(p.Lots)), lotsByWant: make([][]int, len(p.Wants))}
+	storeIndex := make(map[string]int)
+	for i := range p.Lots {
+		l := &p.Lots[i]
+		var matched bool
+		for w := range p.Wants {
+			if l.Quantity > 0 && l.Matches(p.Wants[w]) {
+				s.lotsByWant[w] = append(s.lotsByWant[w], i)
+				matched = true
+			}
+		}
+		if !matched {
+			s.lotStore[i] = -1
+			continue
+		}
+		si, ok := storeIndex[l.SellerUsername]
+		if !ok {
+			si = len(s.stores)
+			storeIndex[l.SellerUsername] = si
+			store, ok := p.Stores[l.SellerUsername]
+			if !ok {
+				store = Store{Username: l.SellerUsername}
+			}
+			s.stores = append(s.stores, store)
+		}
+		s.lotStore[i] = si
+	}
+	return s
+}
+
+// evaluate fills the wants from the lots in the open stores. Every open
+// store is charged shipping and any shortfall from its minimum buy, so a
+// store that is open but unused only adds cost.
+func (s *solver) evaluate(open []bool) *result {
+	r := &result{missingByWant: make([]int, len(s.p.Wants))}
+	subtotals := make([]money.Decimal, len(s.stores))
+	var candidates []int
+	for w, want := range s.p.Wants {
+		need := want.Quantity
+		candidates = candidates[:0]
+		for _, l := range s.lotsByWant[w] {
+			if open[s.lotStore[l]] {
+				candidates = append(candidates, l)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return s.bestUnit(candidates[i], need) < s.bestUnit(candidates[j], need)
+		})
+		for _, l := range candidates {
+			if need <= 0 {
+				break
+			}
+			qty := s.p.Lots[l].Quantity
+			if qty > need {
+				qty = need
+			}
+			unit := s.p.Lots[l].UnitPrice(qty)
+			r.purchases = append(r.purchases, purchase{l, w, qty, unit})
+			cost := unit.MulInt(qty)
+			subtotals[s.lotStore[l]] = subtotals[s.lotStore[l]].Add(cost)
+			r.items = r.items.Add(cost)
+			need -= qty
+		}
+		if need > 0 {
+			r.missingByWant[w] = need
+			r.missing += need
+		}
+	}
+	r.cost = r.items
+	for i, store := range s.stores {
+		if open[i] {
+			r.cost = r.cost.Add(store.Shipping)
+			if subtotals[i] < store.MinBuy {
+				r.cost = r.cost.Add(store.MinBuy.Sub(subtotals[i]))
+			}
+		}
+	}
+	return r
+}
+
+func (s *solver) bestUnit(l, need int) money.Decimal {
+	qty := s.p.Lots[l].Quantity
+	if qty > need {
+		qty = need
+	}
+	return s.p.Lots[l].UnitPrice(qty)
+}
+
+// branchAndBound searches the quantity bought from each lot for the plan
+// that leaves the fewest units missing at the lowest cost, pricing every
+// purchase at its tier and charging each store used its shipping and any
+// shortfall from its minimum buy. Lots are never bought beyond the wanted
+// quantity. The search starts from the greedy plan and, when limit is
+// positive, stops after visiting limit nodes; it reports whether it
+// finished, in which case the result is optimal.
+func (s *solver) branchAndBound(limit int) (*result, bool) {
+	b := &branch{
+		s:             s,
+		limit:         limit,
+		best:          s.greedy(),
+		remaining:     make([]int, len(s.p.Lots)),
+		subtotals:     make([]money.Decimal, len(s.stores)),
+		used:          make([]int, len(s.stores)),
+		missingByWant: make([]int, len(s.p.Wants)),
+		lots:          make([][]int, len(s.p.Wants)),
+		minUnit:       make([]money.Decimal, len(s.p.Wants)),
+		fillable:      make([]int, len(s.p.Wants)),
+		forced:        make([]int, len(s.p.Wants)+1),
+	}
+	for l, lot := range s.p.Lots {
+		b.remaining[l] = lot.Quantity
+	}
+	for w, want := range s.p.Wants {
+		lots := append([]int(nil), s.lotsByWant[w]...)
+		sort.SliceStable(lots, func(i, j int) bool {
+			return s.bestUnit(lots[i], want.Quantity) < s.bestUnit(lots[j], want.Quantity)
+		})
+		b.lots[w] = lots
+		var avail int
+		for k, l := range lots {
+			avail += s.p.Lots[l].Quantity
+			for _, price := range s.p.Lots[l].prices() {
+				if k == 0 || price < b.minUnit[w] {
+					b.minUnit[w] = price
+				}
+			}
+		}
+		b.fillable[w] = want.Quantity
+		if avail < want.Quantity {
+			b.fillable[w] = avail
+		}
+	}
+	for w := len(s.p.Wants) - 1; w >= 0; w-- {
+		b.forced[w] = b.forced[w+1] + s.p.Wants[w].Quantity - b.fillable[w]
+	}
+	b.want(0)
+	return b.best, !b.stopped
+}
+
+// prices returns the unit prices of the lot at every quantity.
+func (l *Lot) prices() []money.Decimal {
+	prices := []money.Decimal{l.Price}
+	for _, t := range l.Tiers {
+		if t.Quantity > 0 {
+			prices = append(prices, t.Price)
+		}
+	}
+	return prices
+}
+
+// branch is the state of a branch and bound search.
+type branch struct {
+	s             *solver
+	limit, nodes  int
+	stopped       bool
+	best          *result
+	remaining     []int           // Quantity left in each lot
+	subtotals     []money.Decimal // By store
+	used          []int           // Number of purchases by store
+	purchases     []purchase
+	missing       int
+	missingByWant []int
+	lots          [][]int         // Lots of each want, cheapest first
+	minUnit       []money.Decimal // Lowest unit price of each want
+	fillable      []int           // Quantity of each want that its lots can fill
+	forced        []int           // Units of wants w and later that cannot be filled
+}
+
+// want tries every quantity to take for want w, largest first.
+func (b *branch) want(w int) {
+	if w == len(b.s.p.Wants) {
+		if r := b.result(); r.better(b.best) {
+			b.best = r
+		}
+		return
+	}
+	need := b.s.p.Wants[w].Quantity
+	avail := 0
+	for _, l := range b.lots[w] {
+		avail += b.remaining[l]
+	}
+	take := need
+	if avail < take {
+		take = avail
+	}
+	for ; take >= 0 && !b.stopped; take-- {
+		missing := need - take
+		if b.missing+missing+b.forced[w+1] > b.best.missing {
+			break
+		}
+		b.missing += missing
+		b.missingByWant[w] = missing
+		b.fill(w, 0, take)
+		b.missing -= missing
+		b.missingByWant[w] = 0
+	}
+}
+
+// fill splits the quantity left of want w among its lots from the k-th.
+func (b *branch) fill(w, k, left int) {
+	if b.stopped || b.pruned(w, left) {
+		return
+	}
+	if left == 0 {
+		b.want(w + 1)
+		return
+	}
+	lots := b.lots[w]
+	if k == len(lots) {
+		return
+	}
+	if b.nodes++; b.limit > 0 && b.nodes > b.limit {
+		b.stopped = true
+		return
+	}
+	rest := 0
+	for _, l := range lots[k+1:] {
+		rest += b.remaining[l]
+	}
+	l := lots[k]
+	si := b.s.lotStore[l]
+	qty := b.remaining[l]
+	if qty > left {
+		qty = left
+	}
+	for ; qty >= 0 && qty >= left-rest; qty-- {
+		if qty == 0 {
+			b.fill(w, k+1, left)
+			continue
+		}
+		unit := b.s.p.Lots[l].UnitPrice(qty)
+		cost := unit.MulInt(qty)
+		b.purchases = append(b.purchases, purchase{l, w, qty, unit})
+		b.remaining[l] -= qty
+		b.subtotals[si] = b.subtotals[si].Add(cost)
+		b.used[si]++
+		b.fill(w, k+1, left-qty)
+		b.used[si]--
+		b.subtotals[si] = b.subtotals[si].Sub(cost)
+		b.remaining[l] += qty
+		b.purchases = b.purchases[:len(b.purchases)-1]
+	}
+}
+
+// pruned reports whether no completion of the search, with left units of
+// want w still to buy, can do better than the best result. The cost of a
+// store used is at least its shipping plus the greater of its minimum buy
+// and its subtotal so far, and each unit still to buy costs at least the
+// lowest unit price of its want.
+func (b *branch) pruned(w, left int) bool {
+	missing := b.missing + b.forced[w+1]
+	if missing != b.best.missing {
+		return missing > b.best.missing
+	}
+	var charged, items money.Decimal
+	for si, store := range b.s.stores {
+		if b.used[si] == 0 {
+			continue
+		}
+		sub := b.subtotals[si]
+		charged = charged.Add(store.Shipping)
+		items = items.Add(store.Shipping).Add(sub)
+		if sub < store.MinBuy {
+			sub = store.MinBuy
+		}
+		charged = charged.Add(sub)
+	}
+	items = items.Add(b.minUnit[w].MulInt(left))
+	for v := w + 1; v < len(b.s.p.Wants); v++ {
+		items = items.Add(b.minUnit[v].MulInt(b.fillable[v]))
+	}
+	return charged >= b.best.cost || items >= b.best.cost
+}
+
+// result returns the cost of the current purchases as a result.
+func (b *branch) result() *result {
+	r := &result{
+		missing:       b.missing,
+		missingByWant: append([]int(nil), b.missingByWant...),
+		purchases:     append([]purchase(nil), b.purchases...),
+	}
+	for si, store := range b.s.stores {
+		if b.used[si] == 0 {
+			continue
+		}
+		r.items = r.items.Add(b.subtotals[si])
+		r.cost = r.cost.Add(store.Shipping).Add(b.subtotals[si])
+		if b.subtotals[si] < store.MinBuy {
+			r.cost = r.cost.Add(store.MinBuy.Sub(b.subtotals[si]))
+		}
+	}
+	return r
+}
+
+// greedy opens the store with the lowest added cost per newly filled unit
+// until no store fills more, then opens or closes single stores while that
+// lowers the cost.
+func (s *solver) greedy() *result {
+	n := len(s.stores)
+	open := make([]bool, n)
+	cur := s.evaluate(open)
+	for {
+		bestStore := -1
+		var bestScore float64
+		var bestResult *result
+		for i := 0; i < n; i++ {
+			if open[i] {
+				continue
+			}
+			open[i] = true
+			r := s.evaluate(open)
+			open[i] = false
+			gain := cur.missing - r.missing
+			if gain <= 0 {
+				continue
+			}
+			if score := r.cost.Sub(cur.cost).Float64() / float64(gain); bestStore < 0 || score < bestScore {
+				bestStore, bestScore, bestResult = i, score, r
+			}
+		}
+		if bestStore < 0 {
+			break
+		}
+		open[bestStore] = true
+		cur = bestResult
+	}
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n; i++ {
+			open[i] = !open[i]
+			if r := s.evaluate(open); r.better(cur) {
+				cur = r
+				improved = true
+			} else {
+				open[i] = !open[i]
+			}
+		}
+	}
+	return cur
+}
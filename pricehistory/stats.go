@@ -0,0 +1,168 @@
+package pricehistory
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Point is a price at a time.
+type Point struct {
+	Time  time.Time
+	Price float64
+}
+
+// Prices returns the prices of points.
+func Prices(points []Point) []float64 {
+	prices := make([]float64, len(points))
+	for i, p := range points {
+		prices[i] = p.Price
+	}
+	return prices
+}
+
+// MovingAverage returns, for each point, the average price of the points in
+// the window of time ending at it, which always includes the point itself.
+// Points must be in order of time.
+func MovingAverage(points []Point, window time.Duration) []Point {
+	averages := make([]Point, len(points))
+	start := 0
+	sum := 0.0
+	for i, p := range points {
+		sum += p.Price
+		for start < i && !points[start].Time.After(p.Time.Add(-window)) {
+			sum -= points[start].Price
+			start++
+		}
+		averages[i] = Point{p.Time, sum / float64(i-start+1)}
+	}
+	return averages
+}
+
+// Mean returns the average of prices. It is false when prices is empty.
+func Mean(prices []float64) (float64, bool) {
+	if len(prices) == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, p := range prices {
+		sum += p
+	}
+	return sum / float64(len(prices)), true
+}
+
+// Percentile returns the pth percentile of prices, for p from 0 to 100,
+// interpolating between the closest ranks. It is false when prices is empty.
+func Percentile(prices []float64, p float64) (float64, bool) {
+	if len(prices) == 0 {
+		return 0, false
+	}
+	sorted := sortedCopy(prices)
+	return percentile(sorted, p), true
+}
+
+func percentile(sorted []float64, p float64) float64 {
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	i := int(rank)
+	if i+1 == len(sorted) {
+		return sorted[i]
+	}
+	return sorted[i] + (rank-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// TrimmedMean returns the average of prices after dropping the given
+// fraction of prices from each end, such as 0.1 to drop the lowest and
+// highest 10%. It is false when no prices remain.
+func TrimmedMean(prices []float64, trim float64) (float64, bool) {
+	sorted := sortedCopy(prices)
+	n := int(trim * float64(len(sorted)))
+	if trim < 0 || 2*n >= len(sorted) {
+		return 0, false
+	}
+	return Mean(sorted[n : len(sorted)-n])
+}
+
+// InterquartileMean returns the average of prices within 1.5 times the
+// interquartile range of the quartiles, the usual rule for outliers. It is
+// false when prices is empty.
+func InterquartileMean(prices []float64) (float64, bool) {
+	if len(prices) == 0 {
+		return 0, false
+	}
+	sorted := sortedCopy(prices)
+	q1, q3 := percentile(sorted, 25), percentile(sorted, 75)
+	low, high := q1-1.5*(q3-q1), q3+1.5*(q3-q1)
+	var kept []float64
+	for _, p := range sorted {
+		if low <= p && p <= high {
+			kept = append(kept, p)
+		}
+	}
+	return Mean(kept)
+}
+
+// Volatility returns the standard deviation of the log returns between
+// consecutive points. Points must be in order of time and have positive
+// prices; others are skipped. It is false when there are fewer than two
+// returns.
+func Volatility(points []Point) (float64, bool) {
+	var returns []float64
+	var prev float64
+	for _, p := range points {
+		if p.Price <= 0 {
+			continue
+		}
+		if prev != 0 {
+			returns = append(returns, math.Log(p.Price/prev))
+		}
+		prev = p.Price
+	}
+	if len(returns) < 2 {
+		return 0, false
+	}
+	mean, _ := Mean(returns)
+	sum := 0.0
+	for _, r := range returns {
+		sum += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(sum / float64(len(returns)-1)), true
+}
+
+// AnnualGrowth estimates the yearly rate at which prices change, such as
+// 0.05 for 5% appreciation, by fitting an exponential trend to points with
+// positive prices. It is false when the points span no time.
+func AnnualGrowth(points []Point) (float64, bool) {
+	var xs, ys []float64
+	for _, p := range points {
+		if p.Price > 0 {
+			xs = append(xs, float64(p.Time.Unix())/(365.25*24*60*60))
+			ys = append(ys, math.Log(p.Price))
+		}
+	}
+	if len(xs) < 2 {
+		return 0, false
+	}
+	xMean, _ := Mean(xs)
+	yMean, _ := Mean(ys)
+	var sxy, sxx float64
+	for i := range xs {
+		sxy += (xs[i] - xMean) * (ys[i] - yMean)
+		sxx += (xs[i] - xMean) * (xs[i] - xMean)
+	}
+	if sxx == 0 {
+		return 0, false
+	}
+	return math.Exp(sxy/sxx) - 1, true
+}
+
+func sortedCopy(prices []float64) []float64 {
+	sorted := append([]float64(nil), prices...)
+	sort.Float64s(sorted)
+	return sorted
+}
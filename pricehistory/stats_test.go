@@ -0,0 +1,64 @@
+package pricehistory
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestStats(t *testing.T) {
+	prices := []float64{5, 1, 3, 2, 4, 100}
+	if p, _ := Percentile(prices, 50); !approx(p, 3.5) {
+		t.Errorf("median = %v, want 3.5", p)
+	}
+	if p, _ := Percentile(prices, 0); p != 1 {
+		t.Errorf("0th percentile = %v, want 1", p)
+	}
+	if m, _ := TrimmedMean(prices, 0.2); !approx(m, 3.5) {
+		t.Errorf("trimmed mean = %v, want 3.5", m)
+	}
+	if m, _ := InterquartileMean(prices); !approx(m, 3) {
+		t.Errorf("interquartile mean = %v, want 3", m)
+	}
+	if _, ok := TrimmedMean(prices, 0.5); ok {
+		t.Error("expected no prices after trimming half from each end")
+	}
+	if _, ok := Percentile(nil, 50); ok {
+		t.Error("expected no percentile of empty prices")
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+	points := []Point{{day(1), 1}, {day(2), 2}, {day(3), 3}, {day(10), 10}}
+	got := Prices(MovingAverage(points, 48*time.Hour))
+	want := []float64{1, 1.5, 2.5, 10}
+	for i := range want {
+		if !approx(got[i], want[i]) {
+			t.Errorf("average %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTrend(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	year := 365.25 * 24 * time.Hour
+	var points []Point
+	for i := 0; i < 4; i++ {
+		points = append(points, Point{start.Add(time.Duration(i) * year), 10 * math.Pow(1.1, float64(i))})
+	}
+	if g, ok := AnnualGrowth(points); !ok || !approx(g, 0.1) {
+		t.Errorf("growth = %v, want 0.1", g)
+	}
+	if v, ok := Volatility(points); !ok || !approx(v, 0) {
+		t.Errorf("volatility = %v, want 0", v)
+	}
+	points[2].Price = 20
+	if v, _ := Volatility(points); v <= 0.1 {
+		t.Errorf("volatility = %v, want above 0.1", v)
+	}
+}
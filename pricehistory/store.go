@@ -0,0 +1,277 @@
+// Package pricehistory records BrickLink price guide snapshots over time
+// and analyzes the trends in them.
+//
+// Each series is identified by a Key and stored as a JSON file in a
+// directory, so no database server is needed. Recording a sold price guide
+// also keeps its individual sales, which are deduplicated across snapshots,
+// so that the history extends past the six months the price guide covers.
+package pricehistory
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+)
+
+// Key identifies a price guide series.
+type Key struct {
+	ItemType     bricklinkstore.ItemType     `json:"item_type"`
+	ItemNo       string                      `json:"item_no"`
+	ColorID      int                         `json:"color_id"`
+	NewOrUsed    bricklinkstore.NewOrUsed    `json:"new_or_used"`
+	GuideType    bricklinkstore.GuideType    `json:"guide_type"`
+	Region       bricklinkstore.Region       `json:"region,omitempty"`
+	CountryCode  bricklinkstore.CountryCode  `json:"country_code,omitempty"`
+	CurrencyCode bricklinkstore.CurrencyCode `json:"currency_code,omitempty"`
+}
+
+// KeyOf returns the key of the price guide requested with the given
+// options. The API defaults are filled in, so that requests with and
+// without explicit defaults share a series.
+func KeyOf(itemType bricklinkstore.ItemType, itemNo string, options *bricklinkstore.PriceGuideOptions) Key {
+	k := Key{ItemType: itemType, ItemNo: itemNo}
+	if options != nil {
+		k.ColorID = options.ColorID
+		k.NewOrUsed = options.NewOrUsed
+		k.GuideType = options.GuideType
+		k.Region = options.Region
+		k.CountryCode = options.CountryCode
+		k.CurrencyCode = options.CurrencyCode
+	}
+	if k.NewOrUsed == "" {
+		k.NewOrUsed = bricklinkstore.NewOrUsedNew
+	}
+	if k.GuideType == "" {
+		k.GuideType = bricklinkstore.GuideTypeStock
+	}
+	return k
+}
+
+func (k Key) String() string {
+	s := fmt.Sprintf("%s %s color %d %s %s", k.ItemType, k.ItemNo, k.ColorID, k.NewOrUsed, k.GuideType)
+	if k.Region != "" {
+		s += " " + string(k.Region)
+	}
+	if k.CountryCode != "" {
+		s += " " + string(k.CountryCode)
+	}
+	if k.CurrencyCode != "" {
+		s += " " + string(k.CurrencyCode)
+	}
+	return s
+}
+
+func (k Key) filename() string {
+	parts := []string{
+		string(k.ItemType), k.ItemNo, fmt.Sprint(k.ColorID), string(k.NewOrUsed),
+		string(k.GuideType), string(k.Region), string(k.CountryCode), string(k.CurrencyCode),
+	}
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "_") + ".json"
+}
+
+// Snapshot is the summary of a price guide at a point in time.
+type Snapshot struct {
+	Time          time.Time `json:"time"`
+	MinPrice      float64   `json:"min_price"`
+	MaxPrice      float64   `json:"max_price"`
+	AvgPrice      float64   `json:"avg_price"`
+	QtyAvgPrice   float64   `json:"qty_avg_price"`
+	UnitQuantity  int       `json:"unit_quantity"`
+	TotalQuantity int       `json:"total_quantity"`
+}
+
+// Sale is a sale listed in a sold price guide.
+type Sale struct {
+	DateOrdered       time.Time                  `json:"date_ordered"`
+	Quantity          int                        `json:"quantity"`
+	UnitPrice         float64                    `json:"unit_price"`
+	SellerCountryCode bricklinkstore.CountryCode `json:"seller_country_code"`
+	BuyerCountryCode  bricklinkstore.CountryCode `json:"buyer_country_code"`
+}
+
+// Series is the recorded history of a price guide.
+type Series struct {
+	Key       Key        `json:"key"`
+	Snapshots []Snapshot `json:"snapshots"` // In order of time
+	Sales     []Sale     `json:"sales"`     // In order of date ordered
+}
+
+// AvgPrices returns the average price of each snapshot.
+func (s *Series) AvgPrices() []Point {
+	points := make([]Point, len(s.Snapshots))
+	for i, snapshot := range s.Snapshots {
+		points[i] = Point{snapshot.Time, snapshot.AvgPrice}
+	}
+	return points
+}
+
+// QtyAvgPrices returns the quantity weighted average price of each snapshot.
+func (s *Series) QtyAvgPrices() []Point {
+	points := make([]Point, len(s.Snapshots))
+	for i, snapshot := range s.Snapshots {
+		points[i] = Point{snapshot.Time, snapshot.QtyAvgPrice}
+	}
+	return points
+}
+
+// SalePrices returns the unit price of each sale ordered in [from, to).
+// A zero from or to leaves that end unbounded.
+func (s *Series) SalePrices(from, to time.Time) []Point {
+	var points []Point
+	for _, sale := range s.Sales {
+		if (!from.IsZero() && sale.DateOrdered.Before(from)) || (!to.IsZero() && !sale.DateOrdered.Before(to)) {
+			continue
+		}
+		points = append(points, Point{sale.DateOrdered, sale.UnitPrice})
+	}
+	return points
+}
+
+// Store is a directory of price guide series.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// Open opens a store in a directory, creating it if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Record adds a price guide retrieved at a time to its series. Identical
+// sales can occur, so a sale is added only as many times as it occurs in
+// the guide beyond the copies already recorded.
+func (s *Store) Record(key Key, guide *bricklinkstore.PriceGuide, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	series, err := s.load(key)
+	if err != nil {
+		return err
+	}
+	series.Snapshots = append(series.Snapshots, Snapshot{
+		Time:          at,
+		MinPrice:      guide.MinPrice.Float64(),
+		MaxPrice:      guide.MaxPrice.Float64(),
+		AvgPrice:      guide.AvgPrice.Float64(),
+		QtyAvgPrice:   guide.QtyAvgPrice.Float64(),
+		UnitQuantity:  guide.UnitQuantity,
+		TotalQuantity: guide.TotalQuantity,
+	})
+	sort.SliceStable(series.Snapshots, func(i, j int) bool {
+		return series.Snapshots[i].Time.Before(series.Snapshots[j].Time)
+	})
+	recorded := make(map[Sale]int, len(series.Sales))
+	for _, sale := range series.Sales {
+		recorded[sale]++
+	}
+	for _, detail := range guide.PriceDetail {
+		if detail.DateOrdered.IsZero() {
+			continue // Stock guides have no sales
+		}
+		quantity := detail.Quantity
+		if quantity == 0 {
+			quantity = detail.QuantityDeprecated
+		}
+		sale := Sale{
+			DateOrdered:       detail.DateOrdered.UTC(),
+			Quantity:          quantity,
+			UnitPrice:         detail.UnitPrice.Float64(),
+			SellerCountryCode: detail.SellerCountryCode,
+			BuyerCountryCode:  detail.BuyerCountryCode,
+		}
+		if recorded[sale] > 0 {
+			recorded[sale]--
+		} else {
+			series.Sales = append(series.Sales, sale)
+		}
+	}
+	sort.SliceStable(series.Sales, func(i, j int) bool {
+		return series.Sales[i].DateOrdered.Before(series.Sales[j].DateOrdered)
+	})
+	return s.save(series)
+}
+
+// Series returns the history of a key. It is empty when nothing has been
+// recorded.
+func (s *Store) Series(key Key) (*Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load(key)
+}
+
+// Keys returns the keys of all recorded series.
+func (s *Store) Keys() ([]Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]Key, 0, len(files))
+	for _, file := range files {
+		series, err := readSeries(file)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, series.Key)
+	}
+	return keys, nil
+}
+
+func (s *Store) load(key Key) (*Series, error) {
+	series, err := readSeries(filepath.Join(s.dir, key.filename()))
+	if os.IsNotExist(err) {
+		return &Series{Key: key}, nil
+	}
+	return series, err
+}
+
+func readSeries(filename string) (*Series, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var series Series
+	if err := json.Unmarshal(data, &series); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return &series, nil
+}
+
+// save writes a series to a temporary file then renames it, so that an
+// interrupted write does not lose the history.
+func (s *Store) save(series *Series) error {
+	data, err := json.Marshal(series)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(s.dir, ".series")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), filepath.Join(s.dir, series.Key.filename()))
+}
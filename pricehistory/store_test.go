@@ -0,0 +1,85 @@
+package pricehistory
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+)
+
+func TestRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pricehistory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := KeyOf(bricklinkstore.ItemTypePart, "3001", &bricklinkstore.PriceGuideOptions{
+		ColorID:   5,
+		NewOrUsed: bricklinkstore.NewOrUsedUsed,
+		GuideType: bricklinkstore.GuideTypeSold,
+	})
+	day := func(d int) time.Time { return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC) }
+	amount := func(s string) bricklinkstore.Amount {
+		a, err := bricklinkstore.ParseAmount(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	sale := func(d int, price string) bricklinkstore.PriceDetail {
+		return bricklinkstore.PriceDetail{Quantity: 1, UnitPrice: amount(price), DateOrdered: day(d)}
+	}
+	// Identical sales are kept as many times as the guide with the most
+	// copies has them.
+	first := &bricklinkstore.PriceGuide{AvgPrice: amount("0.10"), PriceDetail: []bricklinkstore.PriceDetail{sale(1, "0.09"), sale(3, "0.11"), sale(3, "0.11"), sale(5, "0.13")}}
+	second := &bricklinkstore.PriceGuide{AvgPrice: amount("0.12"), PriceDetail: []bricklinkstore.PriceDetail{sale(3, "0.11"), sale(5, "0.13"), sale(2, "0.10"), sale(5, "0.13")}}
+	if err := s.Record(key, second, day(6)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Record(key, first, day(4)); err != nil {
+		t.Fatal(err)
+	}
+
+	series, err := s.Series(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series.Key != key {
+		t.Errorf("got key %v, want %v", series.Key, key)
+	}
+	if avg := series.AvgPrices(); len(avg) != 2 || avg[0].Price != 0.10 || avg[1].Price != 0.12 {
+		t.Errorf("unexpected snapshots %v", avg)
+	}
+	sales := series.SalePrices(time.Time{}, time.Time{})
+	want := []float64{0.09, 0.10, 0.11, 0.11, 0.13, 0.13}
+	if len(sales) != len(want) {
+		t.Fatalf("got %d sales, want %d", len(sales), len(want))
+	}
+	for i, p := range sales {
+		if p.Price != want[i] {
+			t.Errorf("sale %d: got %v, want %v", i, p.Price, want[i])
+		}
+	}
+	if n := len(series.SalePrices(day(2), day(5))); n != 3 {
+		t.Errorf("got %d sales in range, want 3", n)
+	}
+
+	keys, err := s.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != key {
+		t.Errorf("got keys %v", keys)
+	}
+	empty, err := s.Series(KeyOf(bricklinkstore.ItemTypePart, "3002", nil))
+	if err != nil || len(empty.Snapshots) != 0 {
+		t.Errorf("expected empty series, got %v, %v", empty, err)
+	}
+}
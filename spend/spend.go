@@ -0,0 +1,337 @@
+// Package spend analyzes purchases from BrickLink stores.
+//
+// Purchases are aggregated by seller, shipping country, month, item type or
+// category to compare the effective cost of parts, including each part's
+// share of shipping and other order costs, and how quickly sellers ship.
+// Amounts are in the display currency of the user, so that orders from
+// stores in different currencies can be summed.
+package spend
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+)
+
+// Purchase is an order placed and its items.
+type Purchase struct {
+	Order bricklinkstore.Order
+	Items []bricklinkstore.OrderItem
+}
+
+// Fetch retrieves the orders placed by the user with their items, both
+// filed and unfiled.
+func Fetch(c *bricklinkstore.Client) ([]Purchase, error) {
+	var orders []bricklinkstore.Order
+	for _, filed := range []bool{false, true} {
+		list, err := c.GetOrdersByStatus("out", nil, nil, filed)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, list...)
+	}
+	purchases := make([]Purchase, 0, len(orders))
+	for _, o := range orders {
+		order, err := c.GetOrder(o.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		batches, err := c.GetOrderItems(o.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		p := Purchase{Order: *order}
+		for _, batch := range batches {
+			p.Items = append(p.Items, batch...)
+		}
+		purchases = append(purchases, p)
+	}
+	return purchases, nil
+}
+
+// Dimension is what purchases are grouped by.
+type Dimension int
+
+// Dimensions of purchases. Orders are split among their items when grouped
+// by item type or category.
+const (
+	BySeller   Dimension = iota // Order.SellerName
+	ByCountry                   // Order.Shipping.Address.CountryCode
+	ByMonth                     // Order.DateOrdered as "2006-01"
+	ByItemType                  // OrderItem.Item.Type
+	ByCategory                  // OrderItem.Item.CategoryID
+)
+
+// Unknown is the key of the group of orders without items when grouping by
+// item type or category.
+const Unknown = "unknown"
+
+func (d Dimension) String() string {
+	switch d {
+	case BySeller:
+		return "seller"
+	case ByCountry:
+		return "country"
+	case ByMonth:
+		return "month"
+	case ByItemType:
+		return "item type"
+	case ByCategory:
+		return "category"
+	}
+	return fmt.Sprintf("Dimension(%d)", int(d))
+}
+
+// Stats are the totals of a group of purchases.
+type Stats struct {
+	Key       string
+	Currency  bricklinkstore.CurrencyCode
+	Orders    int
+	Lots      int
+	Parts     int                   // Total quantity of items
+	Subtotal  bricklinkstore.Amount // Price of the items
+	Shipping  bricklinkstore.Amount
+	Other     bricklinkstore.Amount // Insurance, extra charges and taxes, less credits and coupons
+	Total     bricklinkstore.Amount // Subtotal, Shipping and Other
+	Weight    float64               // Grams, for orders with known weights
+	shipDays  float64
+	shipCount int
+
+	weighedShipping bricklinkstore.Amount // Shipping of the orders with known weights
+}
+
+// CostPerPart returns the effective cost of each part including its share
+// of shipping and other costs.
+func (s *Stats) CostPerPart() float64 {
+	if s.Parts == 0 {
+		return 0
+	}
+	return s.Total.Float64() / float64(s.Parts)
+}
+
+// ShippingShare returns the fraction of the total spent on shipping.
+func (s *Stats) ShippingShare() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.Shipping.Float64() / s.Total.Float64()
+}
+
+// ShippingPerKilogram returns the shipping cost per kilogram of the orders
+// with known weights. It is false when no weight is known.
+func (s *Stats) ShippingPerKilogram() (float64, bool) {
+	if s.Weight == 0 {
+		return 0, false
+	}
+	return s.weighedShipping.Float64() / (s.Weight / 1000), true
+}
+
+// AverageShippingDays returns the average number of days from ordering to
+// shipping. It is false when no orders have shipped.
+func (s *Stats) AverageShippingDays() (float64, bool) {
+	if s.shipCount == 0 {
+		return 0, false
+	}
+	return s.shipDays / float64(s.shipCount), true
+}
+
+// Aggregate groups purchases by a dimension. Groups are ordered by
+// decreasing total. All orders must have the same display currency.
+func Aggregate(purchases []Purchase, by Dimension) ([]Stats, error) {
+	groups := make(map[string]*Stats)
+	orders := make(map[string]map[int]bool)
+	var currency bricklinkstore.CurrencyCode
+	for i := range purchases {
+		p := &purchases[i]
+		c := p.Order.DisplayCost.CurrencyCode
+		if currency == "" {
+			currency = c
+		} else if c != currency {
+			return nil, fmt.Errorf("spend: order %d in %s, not %s", p.Order.OrderID, c, currency)
+		}
+		for _, share := range shares(p, by) {
+			s, ok := groups[share.key]
+			if !ok {
+				s = &Stats{Key: share.key, Currency: currency}
+				groups[share.key] = s
+				orders[share.key] = make(map[int]bool)
+			}
+			s.Lots += share.lots
+			s.Parts += share.parts
+			s.Subtotal += share.subtotal
+			s.Shipping += share.shipping
+			s.Other += share.other
+			s.Total += share.subtotal + share.shipping + share.other
+			s.Weight += share.weight
+			if share.weight != 0 {
+				s.weighedShipping += share.shipping
+			}
+			if !orders[share.key][p.Order.OrderID] {
+				orders[share.key][p.Order.OrderID] = true
+				s.Orders++
+				if days, ok := shippingDays(&p.Order); ok {
+					s.shipDays += days
+					s.shipCount++
+				}
+			}
+		}
+	}
+	stats := make([]Stats, 0, len(groups))
+	for _, s := range groups {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats, nil
+}
+
+func shippingDays(o *bricklinkstore.Order) (float64, bool) {
+	if o.DateOrdered.IsZero() || o.Shipping.DateShipped.IsZero() || o.Shipping.DateShipped.Before(o.DateOrdered) {
+		return 0, false
+	}
+	return o.Shipping.DateShipped.Sub(o.DateOrdered).Hours() / 24, true
+}
+
+// share is the part of an order that falls in a group.
+type share struct {
+	key             string
+	lots, parts     int
+	subtotal        bricklinkstore.Amount
+	shipping, other bricklinkstore.Amount
+	weight          float64
+}
+
+// shares splits a purchase into the groups of a dimension.
+func shares(p *Purchase, by Dimension) []share {
+	cost := p.Order.DisplayCost
+	other := cost.GrandTotal - cost.Subtotal - cost.Shipping
+	parts := 0
+	for _, item := range p.Items {
+		parts += item.Quantity
+	}
+	if parts == 0 {
+		parts = p.Order.TotalCount
+	}
+	whole := share{
+		lots:     len(p.Items),
+		parts:    parts,
+		subtotal: cost.Subtotal,
+		shipping: cost.Shipping,
+		other:    other,
+		weight:   p.Order.TotalWeight,
+	}
+	if whole.lots == 0 {
+		whole.lots = p.Order.UniqueCount
+	}
+	switch by {
+	case BySeller:
+		whole.key = p.Order.SellerName
+		return []share{whole}
+	case ByCountry:
+		if p.Order.Shipping.Address != nil {
+			whole.key = string(p.Order.Shipping.Address.CountryCode)
+		}
+		return []share{whole}
+	case ByMonth:
+		whole.key = p.Order.DateOrdered.Format("2006-01")
+		return []share{whole}
+	}
+	if len(p.Items) == 0 {
+		whole.key = Unknown
+		return []share{whole}
+	}
+
+	// Split costs among items by weight when every item's weight is known,
+	// otherwise by price.
+	weights := make([]float64, len(p.Items))
+	byWeight := p.Order.TotalWeight != 0
+	for i, item := range p.Items {
+		weights[i] = item.Weight
+		if weights[i] == 0 {
+			weights[i] = item.Item.Weight
+		}
+		weights[i] *= float64(item.Quantity)
+		if weights[i] == 0 {
+			byWeight = false
+		}
+	}
+	if !byWeight {
+		for i, item := range p.Items {
+			weights[i] = item.UnitPriceFinalDisplay.MulInt(item.Quantity).Float64()
+		}
+	}
+	shipping := allocate(cost.Shipping, weights)
+	others := allocate(other, weights)
+	result := make([]share, len(p.Items))
+	for i, item := range p.Items {
+		key := string(item.Item.Type)
+		if by == ByCategory {
+			key = strconv.Itoa(item.Item.CategoryID)
+		}
+		result[i] = share{
+			key:      key,
+			lots:     1,
+			parts:    item.Quantity,
+			subtotal: item.UnitPriceFinalDisplay.MulInt(item.Quantity),
+			shipping: shipping[i],
+			other:    others[i],
+		}
+		if byWeight {
+			result[i].weight = weights[i]
+		}
+	}
+	return result
+}
+
+// allocate splits an amount in proportion to weights so that the parts sum
+// exactly to the amount. Equal parts are used when the weights sum to 0.
+func allocate(amount bricklinkstore.Amount, weights []float64) []bricklinkstore.Amount {
+	parts := make([]bricklinkstore.Amount, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	var allocated bricklinkstore.Amount
+	largest := 0
+	for i, w := range weights {
+		fraction := 1 / float64(len(weights))
+		if sum != 0 {
+			fraction = w / sum
+		}
+		parts[i] = bricklinkstore.Amount(math.Round(float64(amount) * fraction))
+		allocated += parts[i]
+		if weights[i] > weights[largest] {
+			largest = i
+		}
+	}
+	parts[largest] += amount - allocated
+	return parts
+}
+
+// WriteReport writes stats as a table.
+func WriteReport(w io.Writer, by Dimension, stats []Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s\torders\tparts\tsubtotal\tshipping\ttotal\tper part\tshipping %%\tdays to ship\t\n", by)
+	for _, s := range stats {
+		days := "-"
+		if d, ok := s.AverageShippingDays(); ok {
+			days = strconv.FormatFloat(d, 'f', 1, 64)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s %s\t%s\t%s\t%.4f\t%.1f\t%s\t\n",
+			s.Key, s.Orders, s.Parts, s.Currency, s.Subtotal.StringFixed(2), s.Shipping.StringFixed(2),
+			s.Total.StringFixed(2), s.CostPerPart(), 100*s.ShippingShare(), days)
+	}
+	return tw.Flush()
+}
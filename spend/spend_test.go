@@ -0,0 +1,178 @@
+package spend
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andrewarchi/brick-apis/bricklinkstore"
+)
+
+func amount(s string) bricklinkstore.Amount {
+	a, err := bricklinkstore.ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func item(itemType bricklinkstore.ItemType, category, quantity int, price string, weight float64) bricklinkstore.OrderItem {
+	return bricklinkstore.OrderItem{
+		Item:                  bricklinkstore.CatalogItem{Type: itemType, CategoryID: category},
+		Quantity:              quantity,
+		UnitPriceFinalDisplay: amount(price),
+		Weight:                weight,
+	}
+}
+
+func testPurchases() []Purchase {
+	day := func(m, d int) time.Time { return time.Date(2020, time.Month(m), d, 0, 0, 0, 0, time.UTC) }
+	return []Purchase{
+		{
+			Order: bricklinkstore.Order{
+				OrderID: 1, SellerName: "alice", DateOrdered: day(1, 1), TotalWeight: 40,
+				Shipping:    bricklinkstore.Shipping{DateShipped: day(1, 3), Address: &bricklinkstore.Address{CountryCode: "US"}},
+				DisplayCost: bricklinkstore.Cost{CurrencyCode: "USD", Subtotal: amount("3.00"), Shipping: amount("4.00"), GrandTotal: amount("7.00")},
+			},
+			Items: []bricklinkstore.OrderItem{
+				item(bricklinkstore.ItemTypePart, 5, 10, "0.10", 1),     // 10 g
+				item(bricklinkstore.ItemTypeMinifig, 65, 1, "2.00", 30), // 30 g
+			},
+		},
+		{
+			Order: bricklinkstore.Order{
+				OrderID: 2, SellerName: "bob", DateOrdered: day(1, 20),
+				Shipping:    bricklinkstore.Shipping{DateShipped: day(1, 27)},
+				DisplayCost: bricklinkstore.Cost{CurrencyCode: "USD", Subtotal: amount("2.00"), Shipping: amount("1.00"), Credit: amount("0.50"), GrandTotal: amount("2.50")},
+			},
+			Items: []bricklinkstore.OrderItem{
+				item(bricklinkstore.ItemTypePart, 5, 20, "0.05", 0),
+				item(bricklinkstore.ItemTypePart, 7, 20, "0.05", 0),
+			},
+		},
+		{
+			Order: bricklinkstore.Order{
+				OrderID: 3, SellerName: "alice", DateOrdered: day(2, 1),
+				DisplayCost: bricklinkstore.Cost{CurrencyCode: "USD", Subtotal: amount("1.00"), Shipping: amount("1.00"), GrandTotal: amount("2.00")},
+			},
+			Items: []bricklinkstore.OrderItem{item(bricklinkstore.ItemTypePart, 5, 4, "0.25", 0)},
+		},
+	}
+}
+
+func find(t *testing.T, stats []Stats, key string) Stats {
+	for _, s := range stats {
+		if s.Key == key {
+			return s
+		}
+	}
+	t.Fatalf("no stats for %q", key)
+	return Stats{}
+}
+
+func TestAggregateBySeller(t *testing.T) {
+	stats, err := Aggregate(testPurchases(), BySeller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || stats[0].Key != "alice" {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	alice := stats[0]
+	if alice.Orders != 2 || alice.Parts != 15 || alice.Total.String() != "9.00" || alice.Weight != 40 {
+		t.Errorf("unexpected alice %+v", alice)
+	}
+	// Only order 1 has a weight, so order 3's shipping is left out.
+	if perKilo, ok := alice.ShippingPerKilogram(); !ok || math.Abs(perKilo-100) > 1e-9 {
+		t.Errorf("alice shipping per kilogram = %v, want 100", perKilo)
+	}
+	if days, ok := alice.AverageShippingDays(); !ok || days != 2 {
+		t.Errorf("alice ships in %v days, want 2", days)
+	}
+	if perPart := alice.CostPerPart(); math.Abs(perPart-0.6) > 1e-9 {
+		t.Errorf("alice cost per part = %v, want 0.6", perPart)
+	}
+	bob := find(t, stats, "bob")
+	if bob.Other.String() != "-0.50" || bob.Total.String() != "2.50" {
+		t.Errorf("unexpected bob %+v", bob)
+	}
+	if days, _ := bob.AverageShippingDays(); days != 7 {
+		t.Errorf("bob ships in %v days, want 7", days)
+	}
+}
+
+func TestAggregateByItem(t *testing.T) {
+	stats, err := Aggregate(testPurchases(), ByItemType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Order 1 shipping is split by weight: 1.00 to parts and 3.00 to the
+	// minifig. Order 2 costs are split by price.
+	parts := find(t, stats, "PART")
+	if parts.Shipping.String() != "3.00" || parts.Total.String() != "6.50" || parts.Orders != 3 {
+		t.Errorf("unexpected parts %+v", parts)
+	}
+	minifigs := find(t, stats, "MINIFIG")
+	if minifigs.Shipping.String() != "3.00" || minifigs.Total.String() != "5.00" || minifigs.Weight != 30 {
+		t.Errorf("unexpected minifigs %+v", minifigs)
+	}
+
+	stats, err = Aggregate(testPurchases(), ByCategory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total bricklinkstore.Amount
+	for _, s := range stats {
+		total += s.Total
+	}
+	if total.String() != "11.50" {
+		t.Errorf("categories total %s, want 11.50", total)
+	}
+	if c := find(t, stats, "7"); c.Total.String() != "1.25" {
+		t.Errorf("category 7 total %s, want 1.25", c.Total)
+	}
+
+	// Orders without items are kept whole.
+	purchases := append(testPurchases(), Purchase{Order: bricklinkstore.Order{
+		OrderID: 4, SellerName: "carol", TotalCount: 8, UniqueCount: 2,
+		DisplayCost: bricklinkstore.Cost{CurrencyCode: "USD", Subtotal: amount("1.50"), Shipping: amount("0.50"), GrandTotal: amount("2.00")},
+	}})
+	for _, by := range []Dimension{ByItemType, ByCategory} {
+		stats, err := Aggregate(purchases, by)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u := find(t, stats, Unknown); u.Orders != 1 || u.Lots != 2 || u.Parts != 8 || u.Total.String() != "2.00" {
+			t.Errorf("%s: unexpected unknown group %+v", by, u)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	parts := allocate(amount("1.00"), []float64{1, 1, 1})
+	if sum := parts[0] + parts[1] + parts[2]; sum != amount("1.00") {
+		t.Errorf("parts %v sum to %s", parts, sum)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	purchases := testPurchases()
+	purchases[1].Order.DisplayCost.CurrencyCode = "EUR"
+	if _, err := Aggregate(purchases, BySeller); err == nil {
+		t.Error("expected error for mixed currencies")
+	}
+	stats, err := Aggregate(testPurchases(), ByMonth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err := WriteReport(&b, ByMonth, stats); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "2020-01") || !strings.Contains(lines[2], "2020-02") {
+		t.Errorf("unexpected report:\n%s", b.String())
+	}
+}